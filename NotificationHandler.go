@@ -25,30 +25,30 @@ func NewNotificationHandler(app *App) *NotificationHandler {
 	}
 }
 
-// SendVideoNotification sends a notification for a new video to the frontend
+// SendVideoNotification adds a new video to the pending clips and brings the
+// window forward so the prompt shows it. Clips already pending keep their place.
 func (nh *NotificationHandler) SendVideoNotification(fileName, filePath string) {
-	// Exit early if context is nil
+	if !nh.app.pending.Add(filePath) {
+		logger.Debug("%s is already pending", fileName)
+	}
+	nh.showPrompt(fileName)
+}
+
+// showPrompt brings the window forward with the pending clips
+func (nh *NotificationHandler) showPrompt(fileName string) {
+	// Exit early if context is nil, the clip is shown once the window is up
 	if nh.app.ctx == nil {
-		logger.Error("Cannot send notification - context is nil")
+		logger.Error("Cannot show notification - context is nil")
 		return
 	}
 
-	logger.Info("Sending video notification for file: %s", fileName)
+	logger.Info("Showing pending clip prompt for file: %s", fileName)
 
-	// Define the payload
-	payload := map[string]string{
-		"fileName": fileName,
-		"filePath": filePath,
-	}
-
-	// First, ensure the window is visible
 	nh.app.ShowFromTray()
-	time.Sleep(500 * time.Millisecond) // Increased wait time
+	time.Sleep(500 * time.Millisecond)
 
-	logger.Debug("Emitting newVideoDetected event with payload: %+v", payload)
-	// Emit the event multiple times with delays to ensure it's caught
-	wailsRuntime.EventsEmit(nh.app.ctx, "newVideoDetected", payload)
-	time.Sleep(200 * time.Millisecond)
+	// Re-send the list in case the window was reloaded while hidden
+	nh.app.emitPendingClips()
 
 	// Always bring window to front and make it visible
 	wailsRuntime.WindowShow(nh.app.ctx)
@@ -64,8 +64,10 @@ func (nh *NotificationHandler) TestNotification() {
 		return
 	}
 
+	// The made-up clip must not come back after a restart
 	logger.Info("Sending test notification")
-	nh.SendVideoNotification("TestVideo.mp4", "C:\\Test\\TestVideo.mp4")
+	nh.app.pending.AddTransient("C:\\Test\\TestVideo.mp4")
+	nh.showPrompt("TestVideo.mp4")
 }
 
 // Notify sends a desktop notification using the appropriate method based on the OS
@@ -146,13 +148,7 @@ func (nh *NotificationHandler) SendVideoSystemNotification(fileName, filePath st
 		nh.SendVideoNotification(fileName, filePath)
 	} else {
 		logger.Info("System notification sent for: %s", fileName)
-		// Still emit the event for the app to handle internally if needed
-		if nh.app.ctx != nil {
-			payload := map[string]string{
-				"fileName": fileName,
-				"filePath": filePath,
-			}
-			wailsRuntime.EventsEmit(nh.app.ctx, "newVideoDetected", payload)
-		}
+		// Still keep the clip pending so the prompt offers it when the window opens
+		nh.app.pending.Add(filePath)
 	}
 }
@@ -1,14 +1,10 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"mime/multipart"
-	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -18,10 +14,12 @@ import (
 	"syscall"
 	"time"
 
+	"autoclipsend/discord"
 	"autoclipsend/logger"
+	"autoclipsend/media"
 	"autoclipsend/version"
+	"autoclipsend/watcher"
 
-	"github.com/fsnotify/fsnotify"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 	"golang.org/x/sys/windows/registry"
 )
@@ -67,6 +65,7 @@ type ClipDisplayData struct {
 	ThumbnailURL string  `json:"thumbnailUrl"`
 	FilePath     string  `json:"filePath"`
 	Status       string  `json:"status"`
+	Sent         bool    `json:"sent"` // Sent before, possibly under another name
 }
 
 // MedalTVClipsData represents the structure of Medal TV's clips.json
@@ -84,15 +83,41 @@ type NVIDIAGallerySettings struct {
 // App struct
 type App struct {
 	ctx                 context.Context
-	watchers            map[string]*fsnotify.Watcher // Multiple watchers for different paths
-	watcherMutex        sync.Mutex                   // Protects watcher access
-	config              *Config
-	configManager       *ConfigManager // Kept for backward compatibility
-	isVisible           bool           // Tracks if window is visible
-	startTime           time.Time      // Track when app started
-	isMonitoring        bool           // Track monitoring status
-	monitoredPaths      []string       // List of currently monitored paths
+	watchers            map[string]*runningWatch // Running watchers keyed by watch entry ID
+	watchEvents         chan watcher.Event       // Events from every running watcher
+	watcherMutex        sync.Mutex               // Protects watcher access
+	configManager       *ConfigManager           // Owns the config, read it through Snapshot
+	isVisible           bool                     // Tracks if window is visible
+	startTime           time.Time                // Track when app started
+	monitor             *Monitor                 // Monitoring lifecycle: stopped, starting, running, paused or error
+	monitoredPaths      []string                 // List of currently monitored paths
 	notificationHandler *NotificationHandler
+	discordClient       *discord.Client  // Webhook client shared by all sends so rate limits are tracked together
+	transcoder          media.Transcoder // ffmpeg by default, kept behind the interface so it can be swapped
+	sendQueue           *SendQueue       // Persistent queue of clips waiting to be sent
+	workDir             *WorkDir         // Scratch folders for extraction and compression output
+	stabilizer          *Stabilizer      // Holds new clips back until the recorder has finished writing them
+
+	// Highest overall progress sent for each running job, so it never goes back
+	progressMutex sync.Mutex
+	jobProgress   map[string]float64
+
+	// Clips recorded while the app was closed, see catchup.go
+	missedMutex     sync.Mutex
+	missedClips     []MissedClip
+	latestProcessed time.Time // Newest clip time dealt with this session
+
+	// Clips an auto-send rule will send once their countdown ends, see rules.go
+	countdownMutex sync.Mutex
+	countdowns     map[string]*AutoSendCountdown
+
+	// Detected clips waiting for the user, see pending.go
+	pending      *PendingStore
+	pendingMutex sync.Mutex
+	pendingWake  *time.Timer // Fires when the next snooze ends
+
+	ledger  *Ledger  // Every clip sent and where, see ledger.go
+	history *History // Every send attempt with its outcome, see history.go
 	// Note: videosSent and audiosSent moved to persistent storage
 }
 
@@ -100,6 +125,7 @@ type App struct {
 type AppStatus struct {
 	Uptime       string `json:"uptime"`
 	IsMonitoring bool   `json:"isMonitoring"`
+	MonitorState string `json:"monitorState"`
 	MonitorPath  string `json:"monitorPath"`
 	VideosSent   int    `json:"videosSent"`
 	AudiosSent   int    `json:"audiosSent"`
@@ -123,34 +149,35 @@ func NewApp() *App {
 		// LoadConfig now always returns a default config, so this shouldn't happen
 	}
 
-	// Ensure config is not nil (safety check)
-	if config == nil {
-		logger.Error("Config is nil, creating default config")
-		config = &Config{
-			WebhookURL:            "",
-			MonitorPath:           `E:\Highlights\Clips\Screen Recording`,
-			MaxFileSize:           10,
-			CheckInterval:         2,
-			StartupInitialization: true,
-			WindowsStartup:        false,
-			RecursiveMonitoring:   false,
-			DesktopShortcut:       false,
-			UseMedalTVPath:        false,
-			UseNVIDIAPath:         false,
-			UseCustomPath:         false,
-		}
-	}
 	app := &App{
-		config:         config,
 		configManager:  configManager,
 		startTime:      time.Now(),
-		isMonitoring:   false,
-		watchers:       make(map[string]*fsnotify.Watcher),
+		watchers:       make(map[string]*runningWatch),
+		watchEvents:    make(chan watcher.Event, 1000),
 		monitoredPaths: make([]string, 0),
+		discordClient:  discord.NewClient(),
+		transcoder:     media.NewFFmpeg(),
+		countdowns:     make(map[string]*AutoSendCountdown),
+		workDir:        NewWorkDir(filepath.Join(filepath.Dir(configManager.configPath), "work")),
 	}
 
 	// Create notification handler after app is initialized
 	app.notificationHandler = NewNotificationHandler(app)
+
+	app.monitor = NewMonitor(app)
+	app.stabilizer = NewStabilizer(app.checkInterval, app.probeReadable, app.monitor.clipReady)
+
+	// Jobs are persisted next to the config so they survive restarts
+	app.sendQueue = NewSendQueue(filepath.Join(filepath.Dir(configManager.configPath), "send_queue.json"), app.processSendJob)
+	app.sendQueue.onChange = func() {
+		if app.ctx != nil {
+			runtime.EventsEmit(app.ctx, "sendQueueUpdated", app.sendQueue.List())
+		}
+	}
+	app.pending = NewPendingStore(filepath.Join(filepath.Dir(configManager.configPath), "pending_clips.json"))
+	app.pending.onChange = app.emitPendingClips
+	app.ledger = NewLedger(filepath.Join(filepath.Dir(configManager.configPath), "sent_ledger.json"))
+	app.history = NewHistory(filepath.Join(filepath.Dir(configManager.configPath), "send_history.jsonl"))
 	logger.Info("Application initialized with config: monitor_path=%s, max_file_size=%dMB",
 		config.MonitorPath, config.MaxFileSize)
 
@@ -165,12 +192,13 @@ func (a *App) startup(ctx context.Context) {
 	a.InitTray()
 
 	// Add debug logging to check configuration
+	config := a.configManager.Snapshot()
 	logger.Info("=== STARTUP DEBUG INFO ===")
-	logger.Info("StartupInitialization: %v", a.config.StartupInitialization)
-	logger.Info("UseMedalTVPath: %v", a.config.UseMedalTVPath)
-	logger.Info("UseNVIDIAPath: %v", a.config.UseNVIDIAPath)
-	logger.Info("UseCustomPath: %v", a.config.UseCustomPath)
-	logger.Info("MonitorPath: %s", a.config.MonitorPath)
+	logger.Info("StartupInitialization: %v", config.StartupInitialization)
+	logger.Info("UseMedalTVPath: %v", config.UseMedalTVPath)
+	logger.Info("UseNVIDIAPath: %v", config.UseNVIDIAPath)
+	logger.Info("UseCustomPath: %v", config.UseCustomPath)
+	logger.Info("MonitorPath: %s", config.MonitorPath)
 
 	// Test Medal TV path detection
 	if medalPath, err := a.GetMedalTVClipFolder(); err == nil {
@@ -181,14 +209,46 @@ func (a *App) startup(ctx context.Context) {
 
 	logger.Info("=== END STARTUP DEBUG INFO ===")
 
+	// Resume any sends interrupted by a crash or reboot, starting from clean work folders
+	a.workDir.Sweep()
+	if err := a.ledger.Load(); err != nil {
+		logger.Error("Failed to load sent clip ledger: %v", err)
+	}
+	if err := a.history.Load(); err != nil {
+		logger.Error("Failed to load send history: %v", err)
+	}
+	if err := a.sendQueue.Load(); err != nil {
+		logger.Error("Failed to load send queue: %v", err)
+	}
+	workers := config.SendWorkers
+	if workers <= 0 {
+		workers = defaultSendWorkers
+	}
+	a.sendQueue.Start(workers)
+
+	// Clips detected but not yet answered before the last exit
+	if err := a.pending.Load(); err != nil {
+		logger.Error("Failed to load pending clips: %v", err)
+	}
+	a.schedulePendingWake()
+
+	// Offer clips recorded while the app wasn't running
+	go a.catchUp()
+
 	// Start file watcher in a goroutine only if startup initialization is enabled
-	if a.config.StartupInitialization {
-		go a.startFileWatcher()
+	if config.StartupInitialization {
+		go a.monitor.Start()
 	} else {
 		logger.Info("StartupInitialization is disabled - file watcher not started automatically")
 	}
 }
 
+// shutdown is called when the application is about to quit
+func (a *App) shutdown(ctx context.Context) {
+	logger.Info("Stopping send queue")
+	a.sendQueue.Stop()
+}
+
 // domReady is called when the DOM is ready
 func (a *App) domReady(ctx context.Context) {
 	logger.Debug("DOM ready event received")
@@ -208,15 +268,18 @@ func (a *App) beforeClose(ctx context.Context) bool {
 	return false
 }
 
-// GetConfig returns the current configuration
+// GetConfig returns a copy of the current configuration
 func (a *App) GetConfig() *Config {
-	return a.config
+	config := a.configManager.Snapshot()
+	return &config
 }
 
 // SetWebhookURL sets the Discord webhook URL
 func (a *App) SetWebhookURL(url string) error {
-	a.config.WebhookURL = url
-	err := a.configManager.SaveConfig(a.config)
+	err := a.configManager.Update(func(c *Config) error {
+		c.WebhookURL = url
+		return nil
+	})
 
 	// Emit event to notify frontend of config changes
 	if err == nil {
@@ -226,274 +289,109 @@ func (a *App) SetWebhookURL(url string) error {
 	return err
 }
 
-// startFileWatcher starts monitoring the specified directories
-func (a *App) startFileWatcher() {
-	a.watcherMutex.Lock()
-	defer a.watcherMutex.Unlock()
-
-	// Check if monitoring is already running
-	if a.isMonitoring {
-		return
-	}
-
-	// Get all paths to monitor
-	pathsToMonitor := a.getActivePaths()
-	if len(pathsToMonitor) == 0 {
-		logger.Info("No paths configured for monitoring")
-		return
-	}
-
-	a.isMonitoring = true
-	a.monitoredPaths = pathsToMonitor
+// createWatcher starts a watcher for an entry and forwards its events to the monitoring loop
+func (a *App) createWatcher(watch activeWatch) error {
+	logger.Info("Creating watcher for path: %s", watch.Path)
 
-	defer func() {
-		a.stopAllWatchers()
-		a.isMonitoring = false
-		a.monitoredPaths = make([]string, 0)
-	}()
-
-	// Create watchers for each path
-	for _, path := range pathsToMonitor {
-		if err := a.createWatcherForPath(path); err != nil {
-			logger.Error("Failed to create watcher for path %s: %v", path, err)
-			continue
+	requested := watcher.Backend(watch.Entry.Backend)
+	opts := a.watchOptions(watch.Entry)
+	w, backend, err := watcher.New(watch.Path, requested, opts)
+	if err != nil {
+		return fmt.Errorf("error watching %s: %v", watch.Path, err)
+	}
+
+	a.watchers[watch.Entry.ID] = &runningWatch{entry: watch.Entry, path: watch.Path, backend: requested, opts: opts, w: w}
+	stopped := a.monitor.Done()
+	go func() {
+		for {
+			select {
+			case event, ok := <-w.Events():
+				if !ok {
+					logger.Debug("Watcher events channel closed for path: %s", watch.Path)
+					return
+				}
+				select {
+				case a.watchEvents <- event:
+				case <-stopped:
+					return // Nothing reads events once monitoring stopped
+				}
+			case err, ok := <-w.Errors():
+				if !ok {
+					return
+				}
+				logger.Error("Watcher error: error from watcher %s: %v", watch.Path, err)
+			}
 		}
-	}
-
-	if len(a.watchers) == 0 {
-		logger.Error("No watchers could be created")
-		return
-	}
-
-	logger.Info("File monitoring started for %d paths: %v", len(a.watchers), pathsToMonitor)
-
-	// Release the mutex before entering the monitoring loop
-	a.watcherMutex.Unlock()
-
-	// Monitor all watchers
-	a.monitorWatchers()
+	}()
 
-	a.watcherMutex.Lock()
+	logger.Info("Created %s watcher for path: %s (recursive: %v)", backend, watch.Path, watch.Entry.Recursive)
+	return nil
 }
 
-// getActivePaths returns all paths that should be monitored
-func (a *App) getActivePaths() []string {
-	var paths []string
-
-	if a.config.UseMedalTVPath {
-		if medalPath, err := a.GetMedalTVClipFolder(); err == nil && medalPath != "" {
-			logger.Info("Adding MedalTV path to monitoring: %s", medalPath)
-			paths = append(paths, medalPath)
-		} else {
-			logger.Warn("MedalTV path enabled but could not get path: %v", err)
-		}
-	}
-
-	if a.config.UseNVIDIAPath {
-		if nvidiaPath, err := a.GetNVIDIACurrentDirectory(); err == nil && nvidiaPath != "" {
-			logger.Info("Adding NVIDIA path to monitoring: %s", nvidiaPath)
-			paths = append(paths, nvidiaPath)
-		} else {
-			logger.Warn("NVIDIA path enabled but could not get path: %v", err)
-		}
-	}
-
-	if a.config.UseCustomPath && a.config.MonitorPath != "" {
-		logger.Info("Adding custom path to monitoring: %s", a.config.MonitorPath)
-		paths = append(paths, a.config.MonitorPath)
+// watchOptions builds the watcher options for an entry
+func (a *App) watchOptions(entry WatchEntry) watcher.Options {
+	return watcher.Options{
+		Recursive: entry.Recursive,
+		Interval:  time.Duration(a.configManager.Snapshot().PollInterval) * time.Second,
 	}
-
-	logger.Info("Total active paths for monitoring: %d", len(paths))
-	return paths
 }
 
-// createWatcherForPath creates a watcher for a specific path
-func (a *App) createWatcherForPath(path string) error {
-	logger.Info("Creating watcher for path: %s", path)
-
-	watcher, err := fsnotify.NewWatcher()
-	if err != nil {
-		return fmt.Errorf("error creating watcher for %s: %v", path, err)
-	}
-
-	// Add the directory to watch
-	err = watcher.Add(path)
-	if err != nil {
-		watcher.Close()
-		return fmt.Errorf("error adding path %s to watcher: %v", path, err)
+// handleWatcherEvent processes a file system event
+func (a *App) handleWatcherEvent(event watcher.Event) {
+	// Recorders write constantly while capturing, so per-event logging stays at debug level
+	logger.Debug("File system event: %s - %s", event.Op, event.Path)
+
+	// The old name of a renamed or deleted file is gone. A rename's new name
+	// arrives as its own Create event, also when moved in from another folder.
+	if event.Op.Has(watcher.Rename) || event.Op.Has(watcher.Remove) {
+		a.stabilizer.Forget(event.Path)
+		return
 	}
 
-	logger.Info("Successfully added main path %s to watcher", path)
-
-	// If recursive monitoring is enabled, add all subdirectories
-	if a.config.RecursiveMonitoring {
-		logger.Info("Recursive monitoring enabled - scanning subdirectories for %s", path)
-		err = a.addSubdirectoriesToWatcher(watcher, path)
-		if err != nil {
-			logger.Warn("Error adding subdirectories for %s: %v", path, err)
-			// Don't fail the entire operation - just warn
-		}
-	} else {
-		logger.Info("Recursive monitoring disabled - watching only %s", path)
+	// Some recorders create the final name empty and fill it in later, or only
+	// touch attributes when done, so Write and Chmod can be the first sign of a clip
+	if event.Op&(watcher.Create|watcher.Write|watcher.Chmod) == 0 {
+		return
 	}
-
-	a.watchers[path] = watcher
-	logger.Info("Created watcher for path: %s (recursive: %v)", path, a.config.RecursiveMonitoring)
-	return nil
-}
-
-// addSubdirectoriesToWatcher recursively adds all subdirectories to a specific watcher
-func (a *App) addSubdirectoriesToWatcher(watcher *fsnotify.Watcher, root string) error {
-	if watcher == nil {
-		return errors.New("watcher is not initialized")
+	created := event.Op.Has(watcher.Create)
+	if created {
+		a.stabilizer.NoteCreated(event.Path)
 	}
-
-	dirCount := 0
-	maxDirs := 10000 // Limit to prevent system overload
-
-	logger.Info("Starting recursive directory scan for: %s", root)
-
-	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			logger.Warn("Error accessing path %s: %v", path, err)
-			return nil // Continue walking despite errors
+	if !a.isClipCandidate(event.Path) {
+		if created {
+			logger.Debug("Ignoring file that isn't a clip: %s", event.Path)
 		}
-
-		if info.IsDir() && path != root {
-			dirCount++
-			if dirCount > maxDirs {
-				logger.Warn("Reached maximum directory limit (%d) for recursive monitoring in %s", maxDirs, root)
-				return filepath.SkipDir
-			}
-
-			if watcher == nil {
-				return errors.New("watcher became nil during operation")
-			}
-
-			err = watcher.Add(path)
-			if err != nil {
-				logger.Error("Error adding subdirectory %s to watcher: %v", path, err)
-				// Continue despite errors - don't fail the entire operation
-			} else {
-				logger.Debug("Added subdirectory to watch: %s", path)
-			}
-		}
-		return nil
-	})
-
-	logger.Info("Recursive scan completed for %s: %d directories added", root, dirCount)
-	return err
-}
-
-// monitorWatchers handles events from all watchers
-func (a *App) monitorWatchers() {
-	// Create channels to merge all watcher events
-	events := make(chan fsnotify.Event, 1000) // Increased buffer size
-	errors := make(chan error, 100)
-
-	// Start goroutines for each watcher
-	for path, watcher := range a.watchers {
-		go func(w *fsnotify.Watcher, p string) {
-			logger.Debug("Started monitoring goroutine for path: %s", p)
-			for {
-				select {
-				case event, ok := <-w.Events:
-					if !ok {
-						logger.Debug("Watcher events channel closed for path: %s", p)
-						return
-					}
-					events <- event
-				case err, ok := <-w.Errors:
-					if !ok {
-						logger.Debug("Watcher errors channel closed for path: %s", p)
-						return
-					}
-					errors <- fmt.Errorf("error from watcher %s: %v", p, err)
-				}
-			}
-		}(watcher, path)
+		return
 	}
 
-	logger.Info("Started monitoring %d paths with enhanced event handling", len(a.watchers))
-
-	// Main monitoring loop
-	eventCount := 0
-	for a.isMonitoring {
-		select {
-		case event := <-events:
-			eventCount++
-			if eventCount%100 == 0 {
-				logger.Debug("Processed %d events so far", eventCount)
-			}
-			a.handleWatcherEvent(event)
-		case err := <-errors:
-			logger.Error("Watcher error: %v", err)
-		case <-time.After(100 * time.Millisecond):
-			// Small timeout to prevent busy waiting
-		}
+	info, err := os.Stat(event.Path)
+	if err != nil || info.IsDir() {
+		return
 	}
 
-	logger.Info("Monitoring stopped after processing %d events", eventCount)
-}
-
-// handleWatcherEvent processes a file system event
-func (a *App) handleWatcherEvent(event fsnotify.Event) {
-	logger.Info("File system event: %s - %s", event.Op, event.Name)
-
-	if event.Op&fsnotify.Create == fsnotify.Create {
-		// If it's a directory and recursive monitoring is enabled, add it to all relevant watchers
-		if a.config.RecursiveMonitoring {
-			if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
-				logger.Debug("New directory detected: %s", event.Name)
-				a.watcherMutex.Lock()
-				addedToWatchers := 0
-				for path, watcher := range a.watchers {
-					// Check if the new directory is under this watched path
-					if strings.HasPrefix(event.Name, path) && watcher != nil {
-						err := watcher.Add(event.Name)
-						if err != nil {
-							logger.Error("Failed to add new directory %s to watcher %s: %v", event.Name, path, err)
-						} else {
-							addedToWatchers++
-							logger.Debug("Added new directory %s to watcher %s", event.Name, path)
-						}
-					}
-				}
-				a.watcherMutex.Unlock()
-				if addedToWatchers > 0 {
-					logger.Info("Added new directory %s to %d watchers", event.Name, addedToWatchers)
-				}
-			}
-		}
-
-		if a.isVideoFile(event.Name) {
-			// Skip compressed files to avoid processing loop
-			// When we send a file to Discord, it might create a "_compressed" version
-			// which would trigger another notification - we want to ignore these
-			if strings.Contains(filepath.Base(event.Name), "_compressed") {
-				logger.Info("Skipping compressed file: %s", event.Name)
-				return
-			}
-
-			logger.Info("New video file detected: %s", event.Name)
-			// Wait a bit for the file to be fully written
-			time.Sleep(time.Duration(a.config.CheckInterval) * time.Second)
-			a.handleNewVideo(event.Name)
-		} else {
-			logger.Info("Non-video file created: %s", event.Name)
-		}
+	// A Write or Chmod on a file from before this session is a recorder or
+	// sync tool touching an old clip. Clips from while the app was closed
+	// are offered by catch-up instead.
+	if !created && !a.stabilizer.Created(event.Path) && !fileCreated(info).After(a.configManager.Snapshot().LastProcessedTime) {
+		logger.Debug("Ignoring change to an existing file: %s", event.Path)
+		return
 	}
+
+	// Recorders keep writing for a while, notify once the file settles.
+	// Repeated events for the same path are deduplicated by the stabilizer.
+	a.stabilizer.Track(event.Path)
 }
 
-// stopAllWatchers closes all active watchers
+// stopAllWatchers closes all active watchers (assumes watcherMutex is locked)
 func (a *App) stopAllWatchers() {
-	for path, watcher := range a.watchers {
-		if watcher != nil {
-			watcher.Close()
-			logger.Debug("Closed watcher for path: %s", path)
-		}
+	// Files still being written are dropped along with the watchers
+	a.stabilizer.CancelAll()
+	for _, running := range a.watchers {
+		running.w.Close()
+		logger.Debug("Closed watcher for path: %s", running.path)
 	}
-	a.watchers = make(map[string]*fsnotify.Watcher)
+	a.watchers = make(map[string]*runningWatch)
+	a.monitoredPaths = make([]string, 0)
 }
 
 // ShowNotification triggers a notification for a new video
@@ -547,26 +445,140 @@ func (a *App) Maximize() {
 	runtime.WindowMaximise(a.ctx)
 }
 
-// SendToDiscord sends the file to Discord via webhook
+// SendToDiscord queues the file for sending and waits until it has been sent
 // Moved from notification.go to app.go for correct method binding
 func (a *App) SendToDiscord(filePath, customName string, audioOnly bool) error {
-	if a.config.WebhookURL == "" {
-		logger.Error("webhook URL not set")
-		return errors.New("webhook URL not set")
+	if len(a.GetClipDestinations(filePath)) == 0 {
+		logger.Error("no webhook destination for %s", filePath)
+		return errors.New("no webhook destination configured")
+	}
+
+	job := a.sendQueue.Enqueue(filePath, customName, audioOnly)
+	return a.sendQueue.Wait(job.ID)
+}
+
+// QueueSend adds the file to the send queue and returns immediately
+func (a *App) QueueSend(filePath, customName string, audioOnly bool) (SendJob, error) {
+	if len(a.GetClipDestinations(filePath)) == 0 {
+		return SendJob{}, errors.New("no webhook destination configured")
 	}
+	return a.sendQueue.Enqueue(filePath, customName, audioOnly), nil
+}
+
+// GetSendQueue returns all queued, running and recently finished send jobs
+func (a *App) GetSendQueue() []SendJob {
+	return a.sendQueue.List()
+}
+
+// RetrySend puts a failed or cancelled job back into the queue. Destinations
+// that already received the clip are not sent to again.
+func (a *App) RetrySend(jobID string) error {
+	return a.sendQueue.Retry(jobID)
+}
+
+// SetSendPriority changes the priority of a pending job, higher runs first
+func (a *App) SetSendPriority(jobID string, priority int) error {
+	return a.sendQueue.SetPriority(jobID, priority)
+}
+
+// CancelSend removes a pending job from the queue, or stops a running send by
+// killing its ffmpeg process or aborting the upload
+func (a *App) CancelSend(jobID string) error {
+	return a.sendQueue.Cancel(jobID)
+}
+
+// processSendJob extracts, compresses and uploads a queued clip and records
+// the attempt in the send history. It runs on a send queue worker.
+func (a *App) processSendJob(ctx context.Context, job *SendJob) error {
+	record := &SendRecord{
+		JobID:     job.ID,
+		Attempt:   job.Attempts,
+		FilePath:  job.FilePath,
+		FileName:  filepath.Base(job.FilePath),
+		AudioOnly: job.AudioOnly,
+		Strategy:  "original",
+		StartedAt: time.Now(),
+	}
+	defer a.clearSendProgress(job.ID)
+	err := a.sendJob(ctx, job, record)
+	a.recordSend(record, err)
+	return err
+}
+
+// sendJob does the work of processSendJob, filling in record as it goes
+func (a *App) sendJob(ctx context.Context, job *SendJob, record *SendRecord) error {
+	filePath, customName, audioOnly := job.FilePath, job.CustomName, job.AudioOnly
+	config := a.configManager.Snapshot() // Settings changed mid-send apply to the next job
+
+	// Pick destinations up front so nothing gets compressed for nowhere
+	clip := a.describeClip(filePath)
+	record.Source, record.GameTitle = clip.Source, clip.GameTitle
+	var destinations []Destination
+	for _, dest := range a.resolveDestinations(clip) {
+		if !job.sentTo(dest.ID) {
+			destinations = append(destinations, dest)
+		}
+	}
+	if len(destinations) == 0 {
+		if len(job.Results) > 0 {
+			return nil // Every destination was reached on an earlier attempt
+		}
+		logger.Error("no webhook destination for %s", filePath)
+		return errors.New("no webhook destination configured")
+	}
+
+	// Check the ledger so renamed or re-synced copies aren't posted twice by
+	// accident. Forum posts look the clip up too, to reuse an earlier post.
+	// Only files that may have been sent before are hashed here.
+	var hash string
+	if config.DuplicatePolicy != DuplicateAllow || hasForumPost(destinations) {
+		var err error
+		if hash, err = a.ledger.CandidateHash(filePath); err != nil {
+			logger.Warn("Could not hash %s, skipping duplicate check: %v", clip.FileName, err)
+		}
+	}
+	destinations, duplicateNote := a.checkDuplicates(hash, clip.FileName, audioOnly, destinations)
+	if len(destinations) == 0 {
+		a.emitSendProgress(job.ID, map[string]interface{}{
+			"stage":    "error",
+			"progress": 0.0,
+			"message":  duplicateNote,
+			"error":    "clip was already sent",
+		})
+		return errors.New("clip was already sent to every destination")
+	}
+	logger.Info("Sending %s (source: %s) to %d destinations", clip.FileName, clip.Source, len(destinations))
+
+	// Intermediate files go to a per-job folder that is removed however the job ends
+	workDir, err := a.workDir.Create(job.ID)
+	if err != nil {
+		logger.Error("error creating work folder: %v", err)
+		a.emitSendProgress(job.ID, map[string]interface{}{
+			"stage":    "error",
+			"progress": 0.0,
+			"message":  "Error creating work folder",
+			"error":    err.Error(),
+		})
+		return errors.New("error creating work folder")
+	}
+	defer a.workDir.Remove(job.ID)
 
 	// Emit initial progress
-	runtime.EventsEmit(a.ctx, "sendProgress", map[string]interface{}{
+	startMessage := "Starting file processing..."
+	if duplicateNote != "" {
+		startMessage = duplicateNote
+	}
+	a.emitSendProgress(job.ID, map[string]interface{}{
 		"stage":    "initializing",
 		"progress": 0.0,
-		"message":  "Starting file processing...",
+		"message":  startMessage,
 	})
 
 	// Check file size
-	_, err := os.Stat(filePath)
+	originalInfo, err := os.Stat(filePath)
 	if err != nil {
 		logger.Error("error getting file info: %v", err)
-		runtime.EventsEmit(a.ctx, "sendProgress", map[string]interface{}{
+		a.emitSendProgress(job.ID, map[string]interface{}{
 			"stage":    "error",
 			"progress": 0.0,
 			"message":  "Error getting file info",
@@ -574,35 +586,35 @@ func (a *App) SendToDiscord(filePath, customName string, audioOnly bool) error {
 		})
 		return errors.New("error getting file info")
 	}
+	record.OriginalSize = originalInfo.Size()
 
 	var finalPath string
-	var cleanup bool
 
 	if audioOnly {
-		runtime.EventsEmit(a.ctx, "sendProgress", map[string]interface{}{
+		a.emitSendProgress(job.ID, map[string]interface{}{
 			"stage":    "extracting",
-			"progress": 0.2,
+			"progress": extractProgressStart,
 			"message":  "Extracting audio from video...",
 		})
-		
+
 		// Extract audio from video
-		finalPath, err = a.extractAudio(filePath)
+		extractStart := time.Now()
+		finalPath, err = a.extractAudio(ctx, filePath, workDir)
+		record.stage("extract", extractStart)
+		record.Strategy = "audio extract"
 		if err != nil {
+			if ctx.Err() != nil {
+				return a.sendCancelled(job.ID)
+			}
 			logger.Error("error extracting audio: %v", err)
-			runtime.EventsEmit(a.ctx, "sendProgress", map[string]interface{}{
+			a.emitSendProgress(job.ID, map[string]interface{}{
 				"stage":    "error",
-				"progress": 0.2,
+				"progress": extractProgressStart,
 				"message":  "Error extracting audio",
 				"error":    err.Error(),
 			})
 			return errors.New("error extracting audio")
 		}
-		cleanup = true
-		defer func() {
-			if cleanup {
-				os.Remove(finalPath)
-			}
-		}()
 	} else {
 		finalPath = filePath
 	}
@@ -611,182 +623,381 @@ func (a *App) SendToDiscord(filePath, customName string, audioOnly bool) error {
 	finalInfo, err := os.Stat(finalPath)
 	if err != nil {
 		logger.Error("error getting final file info: %v", err)
-		runtime.EventsEmit(a.ctx, "sendProgress", map[string]interface{}{
+		a.emitSendProgress(job.ID, map[string]interface{}{
 			"stage":    "error",
-			"progress": 0.3,
+			"progress": processProgressStart,
 			"message":  "Error getting file info",
 			"error":    err.Error(),
 		})
 		return errors.New("error getting final file info")
 	}
 
-	maxSizeBytes := a.config.MaxFileSize * 1024 * 1024
-	if finalInfo.Size() > maxSizeBytes {
+	maxSizeBytes := config.MaxFileSize * 1024 * 1024
+	var parts []string
+	if finalInfo.Size() > maxSizeBytes && !audioOnly && config.OversizeStrategy == OversizeSplit {
+		logger.Info("File size %d bytes exceeds limit of %d bytes, splitting", finalInfo.Size(), maxSizeBytes)
+
+		a.emitSendProgress(job.ID, map[string]interface{}{
+			"stage":    "splitting",
+			"progress": processProgressStart,
+			"message":  "File too large, splitting into parts...",
+		})
+
+		splitStart := time.Now()
+		parts, err = a.splitVideo(ctx, job.ID, finalPath, workDir, maxSizeBytes)
+		record.stage("split", splitStart)
+		if err != nil {
+			if ctx.Err() != nil {
+				return a.sendCancelled(job.ID)
+			}
+			logger.Error("error splitting file: %v", err)
+			a.emitSendProgress(job.ID, map[string]interface{}{
+				"stage":    "error",
+				"progress": processProgressStart,
+				"message":  "Error splitting file",
+				"error":    err.Error(),
+			})
+			return errors.New("error splitting file")
+		}
+		record.Strategy = fmt.Sprintf("split into %d parts", len(parts))
+	} else if finalInfo.Size() > maxSizeBytes {
 		logger.Info("File size %d bytes exceeds limit of %d bytes, starting aggressive compression", finalInfo.Size(), maxSizeBytes)
-		
-		runtime.EventsEmit(a.ctx, "sendProgress", map[string]interface{}{
+
+		a.emitSendProgress(job.ID, map[string]interface{}{
 			"stage":    "compressing",
-			"progress": 0.4,
+			"progress": processProgressStart,
 			"message":  "File too large, compressing...",
 		})
-		
+
 		// Compress the file aggressively
-		compressedPath, err := a.compressFile(finalPath, audioOnly)
+		compressStart := time.Now()
+		compressedPath, strategy, err := a.compressFile(ctx, job.ID, finalPath, workDir, audioOnly)
+		record.stage("compress", compressStart)
 		if err != nil {
+			if ctx.Err() != nil {
+				return a.sendCancelled(job.ID)
+			}
 			logger.Error("error compressing file: %v", err)
-			runtime.EventsEmit(a.ctx, "sendProgress", map[string]interface{}{
+			a.emitSendProgress(job.ID, map[string]interface{}{
 				"stage":    "error",
-				"progress": 0.4,
+				"progress": processProgressStart,
 				"message":  "Error compressing file",
 				"error":    err.Error(),
 			})
 			return errors.New("error compressing file")
 		}
-		
+
 		// Verify compressed file size
 		compressedInfo, err := os.Stat(compressedPath)
 		if err != nil {
 			logger.Error("error getting compressed file info: %v", err)
-			os.Remove(compressedPath)
-			runtime.EventsEmit(a.ctx, "sendProgress", map[string]interface{}{
+			a.emitSendProgress(job.ID, map[string]interface{}{
 				"stage":    "error",
-				"progress": 0.4,
+				"progress": processProgressStart,
 				"message":  "Error getting compressed file info",
 				"error":    err.Error(),
 			})
 			return errors.New("error getting compressed file info")
 		}
-		
+
 		if compressedInfo.Size() > maxSizeBytes {
 			logger.Error("compressed file still too large: %d bytes (limit: %d bytes)", compressedInfo.Size(), maxSizeBytes)
-			os.Remove(compressedPath)
-			runtime.EventsEmit(a.ctx, "sendProgress", map[string]interface{}{
+			a.emitSendProgress(job.ID, map[string]interface{}{
 				"stage":    "error",
-				"progress": 0.4,
+				"progress": processProgressStart,
 				"message":  "Unable to compress file to required size",
 				"error":    fmt.Sprintf("Current: %d bytes, Required: %d bytes", compressedInfo.Size(), maxSizeBytes),
 			})
 			return fmt.Errorf("unable to compress file to required size. Current: %d bytes, Required: %d bytes", compressedInfo.Size(), maxSizeBytes)
 		}
-		
+
 		finalPath = compressedPath
-		cleanup = true
-		defer func() {
-			if cleanup {
-				os.Remove(finalPath)
-			}
-		}()
-		
+		record.Strategy = strategy
+
 		logger.Info("File successfully compressed from %d bytes to %d bytes", finalInfo.Size(), compressedInfo.Size())
 	}
-	
-	// Send to Discord
-	runtime.EventsEmit(a.ctx, "sendProgress", map[string]interface{}{
-		"stage":    "uploading",
-		"progress": 0.8,
-		"message":  "Uploading to Discord...",
-	})
-	
-	err = a.sendFileToDiscord(finalPath, customName)
-	if err != nil {
-		runtime.EventsEmit(a.ctx, "sendProgress", map[string]interface{}{
-			"stage":    "error",
-			"progress": 0.8,
-			"message":  "Error uploading to Discord",
-			"error":    err.Error(),
-		})
-		return err
+
+	if parts == nil {
+		parts = []string{finalPath}
+	}
+	var finalSize int64
+	for _, part := range parts {
+		if info, err := os.Stat(part); err == nil {
+			finalSize += info.Size()
+		}
+	}
+	record.FinalSize = finalSize
+	// Probe what is actually sent, compression may have changed the resolution.
+	// Split parts are copies of the original, which has the full duration.
+	a.probeClip(ctx, &clip, finalPath)
+
+	variant := VariantVideo
+	switch {
+	case audioOnly:
+		variant = VariantAudio
+	case len(parts) > 1:
+		variant = VariantSplit
+	case finalPath != filePath:
+		variant = VariantCompressed
+	}
+	groups := partGroups(parts, maxSizeBytes)
+
+	// Send to every destination, upload progress is reported by sendFileToDiscord
+	a.sendQueue.SetState(job.ID, JobUploading)
+	uploadStart := time.Now()
+	defer record.stage("upload", uploadStart)
+	var results []DestinationResult
+	var sent []SentEntry
+	var failed []string
+	var firstErr error
+	for i, dest := range destinations {
+		msgs, threadID, err := a.sendParts(ctx, job.ID, dest, clip, hash, customName, audioOnly, parts, groups, finalSize, i, len(destinations))
+		if err != nil && ctx.Err() != nil {
+			return a.sendCancelled(job.ID)
+		}
+
+		result := DestinationResult{DestinationID: dest.ID, Name: dest.Name, Success: err == nil, SentAt: time.Now()}
+		if len(msgs) > 0 {
+			result.MessageID, result.ChannelID, result.ThreadID = msgs[0].ID, msgs[0].ChannelID, threadID
+			for _, m := range msgs[1:] {
+				result.PartIDs = append(result.PartIDs, m.ID)
+			}
+		}
+		if err != nil {
+			result.Error = err.Error()
+			failed = append(failed, dest.Name)
+			if firstErr == nil {
+				firstErr = err
+			}
+		} else {
+			sent = append(sent, SentEntry{
+				FilePath:        filePath,
+				Size:            originalInfo.Size(),
+				ModTime:         originalInfo.ModTime(),
+				DestinationID:   dest.ID,
+				DestinationName: dest.Name,
+				MessageID:       result.MessageID,
+				ThreadID:        result.ThreadID,
+				SentAt:          result.SentAt,
+				Variant:         variant,
+			})
+		}
+		a.sendQueue.RecordResult(job.ID, result)
+		results = append(results, result)
+		record.Destinations = append(record.Destinations, result)
+		if len(destinations) > 1 {
+			// Show each destination's outcome as soon as it's known
+			a.emitSendProgress(job.ID, map[string]interface{}{
+				"stage":    "uploading",
+				"progress": uploadProgressStart + float64(i+1)/float64(len(destinations))*(1-uploadProgressStart),
+				"message":  fmt.Sprintf("Finished %d of %d destinations", i+1, len(destinations)),
+				"results":  results,
+			})
+		}
 	}
 
-	// Get file size for statistics
-	fileInfo, _ := os.Stat(finalPath)
-	fileSize := int64(0)
-	if fileInfo != nil {
-		fileSize = fileInfo.Size()
+	// A clip no earlier send could match wasn't hashed up front, do it now so
+	// later copies of it are recognised
+	if len(sent) > 0 && hash == "" {
+		if hash, err = a.ledger.Hash(filePath); err != nil {
+			logger.Warn("Could not hash %s, it won't be recorded as sent: %v", clip.FileName, err)
+		}
 	}
-	
-	// Increment clip count using ConfigManager
-	err = a.configManager.IncrementClipCount(a.config, fileSize)
+	if hash != "" {
+		for _, entry := range sent {
+			entry.Hash = hash
+			a.ledger.Record(entry)
+		}
+	}
+
+	if firstErr != nil {
+		progress := map[string]interface{}{
+			"stage":    "error",
+			"progress": uploadProgressStart,
+			"message":  fmt.Sprintf("Error uploading to %d of %d destinations", len(failed), len(destinations)),
+			"error":    firstErr.Error(),
+			"results":  results,
+		}
+		// Let the frontend tell a deleted webhook apart from a temporary outage
+		if de, ok := discord.AsError(firstErr); ok {
+			progress["errorKind"] = de.Kind
+			progress["statusCode"] = de.StatusCode
+			progress["retryable"] = de.Retryable()
+			progress["maybeSent"] = de.MaybeSent
+			progress["attempts"] = de.Attempts
+		}
+		a.emitSendProgress(job.ID, progress)
+		if len(failed) == len(destinations) {
+			return firstErr
+		}
+		return fmt.Errorf("failed to send to %s: %w", strings.Join(failed, ", "), firstErr)
+	}
+
+	// Count the clip once, with the size of what was uploaded: every part of a
+	// split, not the oversized original
+	err = a.configManager.IncrementClipCount(finalSize)
 	if err != nil {
 		logger.Warn("Failed to update clip statistics: %v", err)
 	}
 
 	// Emit completion
-	runtime.EventsEmit(a.ctx, "sendProgress", map[string]interface{}{
+	a.emitSendProgress(job.ID, map[string]interface{}{
 		"stage":      "complete",
 		"progress":   1.0,
 		"message":    "Successfully sent to Discord!",
 		"isComplete": true,
+		"results":    results,
 	})
 
 	return nil
 }
 
-// sendFileToDiscord sends the file to Discord via webhook
-func (a *App) sendFileToDiscord(filePath, customName string) error {
-	file, err := os.Open(filePath)
-	if err != nil {
-		logger.Error("error opening file: %v", err)
-		return errors.New("error opening file")
-	}
-	defer file.Close()
-
-	// Create multipart form
-	var buf bytes.Buffer
-	writer := multipart.NewWriter(&buf)
-
-	// Add the file
-	part, err := writer.CreateFormFile("file", filepath.Base(filePath))
-	if err != nil {
-		logger.Error("error creating form file: %v", err)
-		return errors.New("error creating form file")
-	}
-
-	_, err = io.Copy(part, file)
-	if err != nil {
-		logger.Error("error copying file: %v", err)
-		return errors.New("error copying file")
-	}
+// sendParts posts the parts of a clip to one destination in order, one
+// message per group. Parts after the first new forum post go into it. If a
+// message fails, the ones already posted are deleted so a retry starts clean.
+// It returns the posted messages and the thread they went to, if any.
+func (a *App) sendParts(ctx context.Context, jobID string, dest Destination, clip ClipInfo, hash, customName string, audioOnly bool, parts []string, groups [][]int, finalSize int64, index, count int) ([]*discord.Message, string, error) {
+	caption := a.renderCaption(dest, customName, clip, finalSize)
+	fileName := a.renderFileName(dest, clip, finalSize, parts[0])
+	if len(parts) > 1 && fileName == "" {
+		fileName = filepath.Base(clip.FilePath)
+	}
+	threadID := a.threadFor(dest, hash)
+
+	var msgs []*discord.Message
+	for g, group := range groups {
+		payload := a.buildPayload(dest, clip, finalSize, partCaption(caption, group, len(parts)), audioOnly)
+		if threadID == "" && dest.ForumPost {
+			payload.ThreadName = a.renderThreadName(dest, clip, finalSize)
+			payload.AppliedTags = dest.ForumTags
+		}
+		var files []discord.File
+		for _, p := range group {
+			files = append(files, discord.File{Path: parts[p], Name: partFileName(fileName, parts[p], p, len(parts))})
+		}
 
-	// Add custom message if provided
-	if customName != "" {
-		payload := map[string]interface{}{
-			"content": customName,
+		msg, err := a.sendFileToDiscord(ctx, jobID, dest, threadID, files, payload, index*len(groups)+g, count*len(groups))
+		if err != nil {
+			for _, m := range msgs {
+				if delErr := a.discordClient.DeleteMessage(context.Background(), discord.InThread(dest.WebhookURL, threadID), m.ID); delErr != nil {
+					logger.Warn("Could not remove part message %s from %s: %v", m.ID, dest.Name, delErr)
+				}
+			}
+			return nil, "", err
+		}
+		msgs = append(msgs, msg)
+		// A message in a thread reports the thread as its channel
+		if threadID == "" && dest.ForumPost {
+			threadID = msg.ChannelID
 		}
-		payloadBytes, _ := json.Marshal(payload)
-		writer.WriteField("payload_json", string(payloadBytes))
 	}
+	return msgs, threadID, nil
+}
 
-	err = writer.Close()
-	if err != nil {
-		logger.Error("error closing writer: %v", err)
-		return errors.New("error closing writer")
+// Where each stage begins on the overall sendProgress scale. Encode progress
+// fills the processing stage, upload progress the rest up to 1.
+const (
+	extractProgressStart = 0.1
+	processProgressStart = 0.2 // Compressing or splitting
+	uploadProgressStart  = 0.5
+)
+
+// sendFileToDiscord streams the file to one destination, retrying on rate limits and transient failures.
+// index and count place this upload within the job's overall progress.
+func (a *App) sendFileToDiscord(ctx context.Context, jobID string, dest Destination, threadID string, files []discord.File, payload discord.Payload, index, count int) (*discord.Message, error) {
+	timeout := time.Duration(a.configManager.Snapshot().UploadTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = defaultUploadTimeout * time.Second
+	}
+
+	req := discord.UploadRequest{
+		FilePath: files[0].Path,
+		FileName: files[0].Name,
+		Files:    files[1:],
+		ThreadID: threadID,
+		Payload:  payload,
+		Timeout:  timeout,
+		OnRetry: func(attempt int, wait time.Duration, err error) {
+			logger.Warn("Discord upload to %s attempt %d failed, retrying in %s: %v", dest.Name, attempt, wait, err)
+			a.emitSendProgress(jobID, map[string]interface{}{
+				"stage":       "retrying",
+				"progress":    uploadProgressStart,
+				"message":     fmt.Sprintf("Discord is busy, retrying in %s...", wait.Round(time.Second)),
+				"attempt":     attempt,
+				"destination": dest.Name,
+			})
+		},
+		OnProgress: func(sent, total int64) {
+			fraction := (float64(index) + float64(sent)/float64(total)) / float64(count)
+			a.emitSendProgress(jobID, map[string]interface{}{
+				"stage":       "uploading",
+				"progress":    uploadProgressStart + fraction*(1-uploadProgressStart),
+				"message":     fmt.Sprintf("Uploading to %s... %.1f / %.1f MB", dest.Name, float64(sent)/(1024*1024), float64(total)/(1024*1024)),
+				"bytesSent":   sent,
+				"bytesTotal":  total,
+				"destination": dest.Name,
+			})
+		},
 	}
 
-	// Send the request
-	req, err := http.NewRequest("POST", a.config.WebhookURL, &buf)
+	msg, err := a.discordClient.Upload(ctx, dest.WebhookURL, req)
 	if err != nil {
-		logger.Error("error creating request: %v", err)
-		return errors.New("error creating request")
+		logger.Error("error sending file to %s: %v", dest.Name, err)
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return msg, nil
+}
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		logger.Error("error sending request: %v", err)
-		return errors.New("error sending request")
+// sendCancelled reports a job that was stopped through CancelSend. Temp files
+// go away with the job's work folder.
+func (a *App) sendCancelled(jobID string) error {
+	logger.Info("Send job %s cancelled", jobID)
+	a.emitSendProgress(jobID, map[string]interface{}{
+		"stage":      "cancelled",
+		"progress":   0.0,
+		"message":    "Send cancelled",
+		"isComplete": true,
+	})
+	return context.Canceled
+}
+
+// emitSendProgress sends a sendProgress event tagged with the job it belongs to.
+// The progress value is held at the job's highest so far, so errors and
+// retries reported at the start of a stage don't move the bar back.
+func (a *App) emitSendProgress(jobID string, progress map[string]interface{}) {
+	progress["jobId"] = jobID
+	if value, ok := progress["progress"].(float64); ok {
+		a.progressMutex.Lock()
+		if a.jobProgress == nil {
+			a.jobProgress = make(map[string]float64)
+		}
+		if last := a.jobProgress[jobID]; value < last {
+			progress["progress"] = last
+		} else {
+			a.jobProgress[jobID] = value
+		}
+		a.progressMutex.Unlock()
+	}
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "sendProgress", progress)
 	}
-	defer resp.Body.Close()
+}
 
-	if resp.StatusCode != 200 && resp.StatusCode != 204 {
-		body, _ := io.ReadAll(resp.Body)
-		logger.Error("discord API error: %d - %s", resp.StatusCode, string(body))
-		return errors.New("discord API error")
+// emitCompressionProgress sends the detailed progress of an encode, with ETA
+// and speed, tagged with the job it belongs to
+func (a *App) emitCompressionProgress(jobID string, progress map[string]interface{}) {
+	progress["jobId"] = jobID
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "compressionProgress", progress)
 	}
+}
 
-	return nil
+// clearSendProgress forgets a finished job's progress, a retry starts again from 0
+func (a *App) clearSendProgress(jobID string) {
+	a.progressMutex.Lock()
+	delete(a.jobProgress, jobID)
+	a.progressMutex.Unlock()
 }
 
 // GetUptime returns the application's uptime
@@ -807,98 +1018,140 @@ func (a *App) GetAppStatus() AppStatus {
 	stats := a.GetStatistics()
 
 	// Get path information
+	config := a.configManager.Snapshot()
 	var medalTVPath, nvidiaPath string
-	if a.config.UseMedalTVPath {
+	if config.UseMedalTVPath {
 		medalTVPath, _ = a.GetMedalTVClipFolder()
 	}
-	if a.config.UseNVIDIAPath {
+	if config.UseNVIDIAPath {
 		nvidiaPath, _ = a.GetNVIDIACurrentDirectory()
 	}
 	return AppStatus{
 		Uptime:       formatDuration(uptime),
-		IsMonitoring: a.isMonitoring,
-		MonitorPath:  a.config.MonitorPath,
+		IsMonitoring: a.monitor.State() == MonitorRunning,
+		MonitorState: string(a.monitor.State()),
+		MonitorPath:  config.MonitorPath,
 		VideosSent:   stats.TotalClips,   // Use total clips from storage
 		AudiosSent:   stats.SessionClips, // Use session clips for audio count
 		Version:      version.FormatVersion(),
-		UseMedalTV:   a.config.UseMedalTVPath,
-		UseNVIDIA:    a.config.UseNVIDIAPath,
-		UseCustom:    a.config.UseCustomPath,
+		UseMedalTV:   config.UseMedalTVPath,
+		UseNVIDIA:    config.UseNVIDIAPath,
+		UseCustom:    config.UseCustomPath,
 		MedalTVPath:  medalTVPath,
 		NVIDIAPath:   nvidiaPath,
 	}
 }
 
-// SaveConfig saves the entire configuration
-func (a *App) SaveConfig(config Config) error {
-	a.config = &config
-	err := a.configManager.SaveConfig(a.config)
+// SaveConfig saves the settings edited on the settings page. settings is
+// keyed by the config's JSON names and only the keys present are changed, so
+// settings the page doesn't show keep their values.
+func (a *App) SaveConfig(settings map[string]interface{}) error {
+	patch, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+
+	err = a.configManager.Update(func(prev *Config) error {
+		// Decode into a fresh config so a bad or partly applied patch never
+		// shares slices with, or leaks into, the live one
+		merged, err := mergeSettings(prev, patch)
+		if err != nil {
+			return err
+		}
+		var config Config
+		if err := json.Unmarshal(merged, &config); err != nil {
+			return err
+		}
+		config.Stats = prev.Stats // Statistics aren't settings
+		if _, ok := settings["watches"]; ok {
+			if err := validateWatchEntries(config.Watches); err != nil {
+				return err
+			}
+		} else {
+			applyLegacyWatchSettings(prev, &config)
+		}
+		if err := validateAutoSendRules(config.AutoSendRules); err != nil {
+			return err
+		}
+		syncLegacyWatchFields(&config)
+
+		*prev = config
+		return nil
+	})
 
 	// Emit event to notify frontend of config changes
-	if err == nil {
+	if err == nil && a.ctx != nil {
 		runtime.EventsEmit(a.ctx, "config-updated")
 	}
 
 	return err
 }
 
-// UpdateMonitorPath updates the monitor path and restarts watcher
-func (a *App) UpdateMonitorPath(path string) error {
-	a.watcherMutex.Lock()
-	defer a.watcherMutex.Unlock()
-
-	// Stop current watchers if running
-	a.stopAllWatchersLocked()
-
-	// Update config
-	a.config.MonitorPath = path
-	err := a.configManager.SaveConfig(a.config)
+// mergeSettings returns config as JSON with the top-level keys of patch replacing its own
+func mergeSettings(config *Config, patch []byte) ([]byte, error) {
+	current, err := json.Marshal(config)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	var fields, changes map[string]json.RawMessage
+	if err := json.Unmarshal(current, &fields); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(patch, &changes); err != nil {
+		return nil, err
+	}
+	for key, value := range changes {
+		fields[key] = value
+	}
+	return json.Marshal(fields)
+}
 
-	// Emit event to notify frontend of config changes
-	runtime.EventsEmit(a.ctx, "config-updated")
-
-	// Restart watchers with new configuration
-	go a.startFileWatcher()
-	return nil
+// UpdateMonitorPath points the custom folder entry at path, restarting only its watcher
+func (a *App) UpdateMonitorPath(path string) error {
+	return a.applyWatchChanges(func(c *Config) error {
+		if i := findWatchEntry(c.Watches, watchIDCustom); i >= 0 {
+			c.Watches[i].Path = path
+		} else {
+			entry := builtinWatchEntry(watchIDCustom, c)
+			entry.Path = path
+			c.Watches = append(c.Watches, entry)
+		}
+		return nil
+	})
 }
 
-// StartMonitoring starts the file monitoring
+// StartMonitoring starts the file monitoring, or resumes it when paused
 func (a *App) StartMonitoring() error {
-	if !a.isMonitoring {
-		go a.startFileWatcher()
-	}
-	return nil
+	return a.monitor.Start()
 }
 
 // RestartMonitoring restarts file monitoring with current configuration
 func (a *App) RestartMonitoring() error {
 	logger.Info("Restarting monitoring with current configuration")
-
-	a.watcherMutex.Lock()
-	defer a.watcherMutex.Unlock()
-
-	// Stop current watchers
-	a.stopAllWatchersLocked()
-
-	// Start new monitoring
-	go a.startFileWatcher()
-
-	logger.Info("Monitoring restart initiated")
-	return nil
+	return a.monitor.Restart()
 }
 
 // StopMonitoring stops the file monitoring
 func (a *App) StopMonitoring() error {
-	a.watcherMutex.Lock()
-	defer a.watcherMutex.Unlock()
-
-	a.stopAllWatchersLocked()
+	a.monitor.Stop()
 	return nil
 }
 
+// PauseMonitoring keeps watching but holds detected clips until ResumeMonitoring
+func (a *App) PauseMonitoring() {
+	a.monitor.Pause()
+}
+
+// ResumeMonitoring handles clips detected during a pause and continues monitoring
+func (a *App) ResumeMonitoring() {
+	a.monitor.Resume()
+}
+
+// GetMonitoringState returns the monitor's state, folders and held events
+func (a *App) GetMonitoringState() MonitorStatus {
+	return a.monitor.Status()
+}
+
 // GetMonitoredPaths returns the currently monitored paths
 func (a *App) GetMonitoredPaths() []string {
 	a.watcherMutex.Lock()
@@ -907,19 +1160,6 @@ func (a *App) GetMonitoredPaths() []string {
 	return append([]string(nil), a.monitoredPaths...) // Return a copy
 }
 
-// stopAllWatchersLocked stops all watchers (assumes mutex is already locked)
-func (a *App) stopAllWatchersLocked() {
-	a.isMonitoring = false
-	for path, watcher := range a.watchers {
-		if watcher != nil {
-			watcher.Close()
-			logger.Debug("Closed watcher for path: %s", path)
-		}
-	}
-	a.watchers = make(map[string]*fsnotify.Watcher)
-	a.monitoredPaths = make([]string, 0)
-}
-
 // SelectFolder opens a folder selection dialog
 func (a *App) SelectFolder() (string, error) {
 	options := runtime.OpenDialogOptions{
@@ -957,13 +1197,14 @@ func intToStr(i int) string {
 
 // GetStatistics returns the current application statistics
 func (a *App) GetStatistics() Stats {
+	config := a.configManager.Snapshot()
 	return Stats{
-		TotalClips:     a.config.TotalClips,
-		LastClipTime:   a.config.LastClipTime,
-		SessionClips:   a.config.SessionClips,
-		TotalSize:      a.config.TotalSize,
-		StartTime:      a.config.StartTime,
-		LastUpdateTime: a.config.LastUpdateTime,
+		TotalClips:     config.TotalClips,
+		LastClipTime:   config.LastClipTime,
+		SessionClips:   config.SessionClips,
+		TotalSize:      config.TotalSize,
+		StartTime:      config.StartTime,
+		LastUpdateTime: config.LastUpdateTime,
 	}
 }
 
@@ -984,16 +1225,17 @@ func (a *App) GetStorageInfo() map[string]interface{} {
 		info["settings_file_exists"] = false
 	}
 
-	info["total_clips"] = a.config.TotalClips
-	info["session_clips"] = a.config.SessionClips
-	info["total_size_mb"] = float64(a.config.TotalSize) / (1024 * 1024)
+	stats := a.GetStatistics()
+	info["total_clips"] = stats.TotalClips
+	info["session_clips"] = stats.SessionClips
+	info["total_size_mb"] = float64(stats.TotalSize) / (1024 * 1024)
 
 	return info
 }
 
 // ExportData exports settings and statistics to a file
 func (a *App) ExportData(filePath string) error {
-	data, err := json.MarshalIndent(a.config, "", "  ")
+	data, err := json.MarshalIndent(a.configManager.Snapshot(), "", "  ")
 	if err != nil {
 		return err
 	}
@@ -1013,18 +1255,20 @@ func (a *App) ImportData(filePath string) error {
 		return err
 	}
 
-	// Keep current session stats, only import settings and total stats
-	importedConfig.SessionClips = a.config.SessionClips
-	importedConfig.StartTime = a.config.StartTime
-	importedConfig.LastUpdateTime = time.Now()
+	return a.configManager.Update(func(c *Config) error {
+		// Keep current session stats, only import settings and total stats
+		importedConfig.SessionClips = c.SessionClips
+		importedConfig.StartTime = c.StartTime
+		importedConfig.LastUpdateTime = time.Now()
 
-	a.config = &importedConfig
-	return a.configManager.SaveConfig(a.config)
+		*c = importedConfig
+		return nil
+	})
 }
 
 // ResetSessionStats resets session-specific statistics
 func (a *App) ResetSessionStats() error {
-	return a.configManager.ResetSessionStats(a.config)
+	return a.configManager.ResetSessionStats()
 }
 
 // GetDataPath returns the application data directory path
@@ -1034,12 +1278,9 @@ func (a *App) GetDataPath() string {
 
 // SetWindowsStartup enables or disables Windows startup
 func (a *App) SetWindowsStartup(enabled bool) error {
-	a.config.WindowsStartup = enabled
-
 	if enabled {
 		err := a.addToWindowsStartup()
 		if err != nil {
-			a.config.WindowsStartup = false // Revert on error
 			logger.Error("failed to add to Windows startup: %v", err)
 			return errors.New("failed to add to Windows startup")
 		}
@@ -1051,7 +1292,10 @@ func (a *App) SetWindowsStartup(enabled bool) error {
 		}
 	}
 
-	return a.configManager.SaveConfig(a.config)
+	return a.configManager.Update(func(c *Config) error {
+		c.WindowsStartup = enabled
+		return nil
+	})
 }
 
 func (a *App) addToWindowsStartup() error {
@@ -1294,12 +1538,9 @@ func (a *App) HasDesktopShortcut() bool {
 
 // SetDesktopShortcut enables or disables desktop shortcut
 func (a *App) SetDesktopShortcut(enabled bool) error {
-	a.config.DesktopShortcut = enabled
-
 	if enabled {
 		err := a.CreateDesktopShortcut()
 		if err != nil {
-			a.config.DesktopShortcut = false // Revert on error
 			logger.Error("failed to create desktop shortcut: %v", err)
 			return errors.New("failed to create desktop shortcut")
 		}
@@ -1311,7 +1552,10 @@ func (a *App) SetDesktopShortcut(enabled bool) error {
 		}
 	}
 
-	return a.configManager.SaveConfig(a.config)
+	return a.configManager.Update(func(c *Config) error {
+		c.DesktopShortcut = enabled
+		return nil
+	})
 }
 
 // GetMedalTVClipFolder reads the clipFolder path from MedalTV's settings.json
@@ -1400,7 +1644,7 @@ func (a *App) GetNVIDIACurrentDirectory() (string, error) {
 
 // isMedalTVClip checks if a file is from Medal TV by comparing its path with the Medal TV clip folder
 func (a *App) isMedalTVClip(filePath string) bool {
-	if !a.config.UseMedalTVPath {
+	if !a.configManager.Snapshot().UseMedalTVPath {
 		return false
 	}
 
@@ -1424,8 +1668,8 @@ func (a *App) isMedalTVClip(filePath string) bool {
 	return strings.HasPrefix(absFilePath, absMedalPath)
 }
 
-// GetMedalTVClips reads and returns all clips from Medal TV's clips.json file
-func (a *App) GetMedalTVClips() ([]ClipDisplayData, error) {
+// loadMedalTVClips reads Medal TV's clips.json, keyed by clip UUID
+func loadMedalTVClips() (map[string]MedalTVClip, error) {
 	// Get Medal TV clips.json path
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
@@ -1436,10 +1680,18 @@ func (a *App) GetMedalTVClips() ([]ClipDisplayData, error) {
 	clipsJSONPath := filepath.Join(appDataPath, "Medal", "store", "clips.json")
 
 	// Check if file exists
-	if _, err := os.Stat(clipsJSONPath); os.IsNotExist(err) {
+	stat, err := os.Stat(clipsJSONPath)
+	if os.IsNotExist(err) {
 		return nil, errors.New("Medal TV clips.json file not found")
 	}
 
+	// Medal rewrites the file when clips change, so an unchanged file needs no re-parse
+	medalClipsCache.Lock()
+	defer medalClipsCache.Unlock()
+	if err == nil && medalClipsCache.clips != nil && stat.ModTime().Equal(medalClipsCache.modTime) && stat.Size() == medalClipsCache.size {
+		return medalClipsCache.clips, nil
+	}
+
 	// Read the file
 	data, err := os.ReadFile(clipsJSONPath)
 	if err != nil {
@@ -1452,6 +1704,28 @@ func (a *App) GetMedalTVClips() ([]ClipDisplayData, error) {
 		return nil, fmt.Errorf("failed to parse clips.json: %v", err)
 	}
 
+	if stat != nil {
+		medalClipsCache.modTime, medalClipsCache.size, medalClipsCache.clips = stat.ModTime(), stat.Size(), clipsMap
+	}
+	return clipsMap, nil
+}
+
+// medalClipsCache holds the last parse of clips.json for loadMedalTVClips.
+// Callers share the map and must not modify it.
+var medalClipsCache struct {
+	sync.Mutex
+	modTime time.Time
+	size    int64
+	clips   map[string]MedalTVClip
+}
+
+// GetMedalTVClips reads and returns all clips from Medal TV's clips.json file
+func (a *App) GetMedalTVClips() ([]ClipDisplayData, error) {
+	clipsMap, err := loadMedalTVClips()
+	if err != nil {
+		return nil, err
+	}
+
 	// Convert to display data and sort by time (latest first)
 	var clips []ClipDisplayData
 	for uuid, clip := range clipsMap {
@@ -1484,6 +1758,7 @@ func (a *App) GetMedalTVClips() ([]ClipDisplayData, error) {
 			ThumbnailURL: clip.Content.ThumbnailURL,
 			FilePath:     clip.FilePath,
 			Status:       clip.Status,
+			Sent:         a.ledger.IsSent(clip.FilePath),
 		}
 		clips = append(clips, clipData)
 	}
@@ -1500,12 +1775,13 @@ func (a *App) GetMedalTVClips() ([]ClipDisplayData, error) {
 	return clips, nil
 }
 
-// SendClipToDiscord sends a specific clip to Discord
-func (a *App) SendClipToDiscord(clipUUID string) error {
+// SendClipToDiscord queues a specific clip for sending and returns the job,
+// whose progress is reported through sendProgress events
+func (a *App) SendClipToDiscord(clipUUID string) (SendJob, error) {
 	// Get the clip data
 	clips, err := a.GetMedalTVClips()
 	if err != nil {
-		return fmt.Errorf("failed to get clips: %v", err)
+		return SendJob{}, fmt.Errorf("failed to get clips: %v", err)
 	}
 
 	// Find the specific clip
@@ -1518,23 +1794,15 @@ func (a *App) SendClipToDiscord(clipUUID string) error {
 	}
 
 	if targetClip == nil {
-		return errors.New("clip not found")
+		return SendJob{}, errors.New("clip not found")
 	}
 
 	// Check if file exists
 	if _, err := os.Stat(targetClip.FilePath); os.IsNotExist(err) {
-		return errors.New("clip file not found")
+		return SendJob{}, errors.New("clip file not found")
 	}
 
-	// Use existing SendToDiscord function with clean title
-	customName := targetClip.Title
-	// Clean up the title - remove any existing timestamp suffixes
-	if idx := strings.LastIndex(customName, "_"); idx > 0 {
-		// Check if everything after the last underscore is digits (timestamp)
-		suffix := customName[idx+1:]
-		if _, err := strconv.ParseInt(suffix, 10, 64); err == nil {
-			customName = customName[:idx]
-		}
-	}
-	return a.SendToDiscord(targetClip.FilePath, customName, false)
+	// The caption comes from the templates, whose default {title} is the
+	// clip title without Medal's numeric suffix
+	return a.QueueSend(targetClip.FilePath, "", false)
 }
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"os/user"
+	"path/filepath"
+	"strings"
+
+	"autoclipsend/caption"
+)
+
+// sourceNames are the human readable names used for {source}
+var sourceNames = map[string]string{
+	SourceMedalTV: "Medal",
+	SourceNVIDIA:  "NVIDIA",
+	SourceCustom:  "Custom",
+	SourceOther:   "Manual",
+}
+
+// templateValues returns what each placeholder expands to for a clip
+func templateValues(clip ClipInfo, size int64) map[string]string {
+	fileName := strings.TrimSuffix(clip.FileName, filepath.Ext(clip.FileName))
+	values := map[string]string{
+		"game":     clip.GameTitle,
+		"title":    firstNonEmpty(caption.CleanTitle(clip.Title), fileName),
+		"filename": fileName,
+		"source":   sourceNames[clip.Source],
+		"user":     currentUserName(),
+		"duration": "",
+		"date":     "",
+		"size":     "",
+	}
+	if clip.Duration > 0 {
+		values["duration"] = formatClipDuration(clip.Duration)
+	}
+	if !clip.RecordedAt.IsZero() {
+		values["date"] = clip.RecordedAt.Format("2006-01-02")
+	}
+	if size > 0 {
+		values["size"] = formatFileSize(size)
+	}
+	return values
+}
+
+// defaultCaptionTemplate is used when neither the destination nor the
+// settings have a caption template
+const defaultCaptionTemplate = "{title}"
+
+// renderCaption picks the caption for a destination. Text typed by the user
+// wins and may contain placeholders itself, then the destination's template,
+// then the global one.
+func (a *App) renderCaption(dest Destination, customName string, clip ClipInfo, size int64) string {
+	tmpl := firstNonEmpty(customName, dest.CaptionTemplate, a.configManager.Snapshot().CaptionTemplate, defaultCaptionTemplate)
+	return strings.TrimSpace(caption.Render(tmpl, templateValues(clip, size)))
+}
+
+// maxThreadName is Discord's limit for thread and forum post titles
+const maxThreadName = 100
+
+// renderThreadName returns the title of a new forum post for the clip
+func (a *App) renderThreadName(dest Destination, clip ClipInfo, size int64) string {
+	tmpl := firstNonEmpty(dest.ThreadNameTemplate, "{title}")
+	name := strings.TrimSpace(caption.Render(tmpl, templateValues(clip, size)))
+	name = firstNonEmpty(name, clip.FileName)
+	if runes := []rune(name); len(runes) > maxThreadName {
+		name = string(runes[:maxThreadName])
+	}
+	return name
+}
+
+// renderFileName returns the attachment name for a destination, or "" to keep the file's own name
+func (a *App) renderFileName(dest Destination, clip ClipInfo, size int64, finalPath string) string {
+	tmpl := firstNonEmpty(dest.FilenameTemplate, a.configManager.Snapshot().FilenameTemplate)
+	if tmpl == "" {
+		return ""
+	}
+
+	name := caption.SanitizeFileName(caption.Render(tmpl, templateValues(clip, size)))
+	if name == "" {
+		return ""
+	}
+	// Keep the real extension, it may have changed during audio extraction
+	return name + filepath.Ext(finalPath)
+}
+
+// currentUserName returns the Windows account name without its domain
+func currentUserName() string {
+	u, err := user.Current()
+	if err != nil {
+		return ""
+	}
+	name := u.Username
+	if idx := strings.LastIndex(name, `\`); idx >= 0 {
+		name = name[idx+1:]
+	}
+	return name
+}
+
+// PreviewTemplate renders a caption or filename template for a file so the UI
+// can show the result before sending. Without a file, sample values are used.
+func (a *App) PreviewTemplate(tmpl, filePath string) string {
+	clip := ClipInfo{
+		FileName:  "Clip_2024-01-01.mp4",
+		Source:    SourceMedalTV,
+		GameTitle: "Valorant",
+		Title:     "Ace on Ascent",
+		Duration:  42,
+	}
+	var size int64 = 8 * 1024 * 1024
+
+	if filePath != "" {
+		clip = a.describeClip(filePath)
+		a.probeClip(context.Background(), &clip, filePath)
+		size = clip.Size
+	}
+
+	return caption.Render(tmpl, templateValues(clip, size))
+}
@@ -0,0 +1,47 @@
+// Package caption renders the caption, forum post title and file name
+// templates sent with a clip. The values come from the caller, so it builds
+// and is tested on any OS.
+package caption
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// placeholderPattern matches {name} placeholders in caption and filename templates
+var placeholderPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// Render replaces known placeholders, matching their names case-insensitively.
+// Unknown ones are left as typed so a typo shows up in the preview instead of
+// silently disappearing.
+func Render(tmpl string, values map[string]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(tmpl, func(match string) string {
+		if v, ok := values[strings.ToLower(match[1:len(match)-1])]; ok {
+			return v
+		}
+		return match
+	})
+}
+
+// CleanTitle removes the numeric suffix Medal appends to clip titles
+func CleanTitle(title string) string {
+	if idx := strings.LastIndex(title, "_"); idx > 0 {
+		// Check if everything after the last underscore is digits (timestamp)
+		if _, err := strconv.ParseInt(title[idx+1:], 10, 64); err == nil {
+			return title[:idx]
+		}
+	}
+	return title
+}
+
+// SanitizeFileName drops characters Windows and Discord don't accept in file names
+func SanitizeFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 32 || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return -1
+		}
+		return r
+	}, name)
+	return strings.Trim(strings.TrimSpace(name), ".")
+}
@@ -0,0 +1,56 @@
+package caption
+
+import "testing"
+
+func TestRender(t *testing.T) {
+	values := map[string]string{"game": "Valorant", "title": "Ace", "size": ""}
+	tests := []struct {
+		name string
+		tmpl string
+		want string
+	}{
+		{"no placeholders", "Look at this", "Look at this"},
+		{"placeholders", "{title} in {game}", "Ace in Valorant"},
+		{"names ignore case", "{Title} in {GAME}", "Ace in Valorant"},
+		{"empty value", "{title} {size}", "Ace "},
+		{"unknown placeholder is kept", "{title} {gmae}", "Ace {gmae}"},
+		{"unclosed brace", "{title", "{title"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Render(tt.tmpl, values); got != tt.want {
+				t.Errorf("Render(%q) = %q, want %q", tt.tmpl, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCleanTitle(t *testing.T) {
+	tests := map[string]string{
+		"Ace on Ascent_1704067200": "Ace on Ascent",
+		"Ace on Ascent":            "Ace on Ascent",
+		"Round_two":                "Round_two",
+		"_1704067200":              "_1704067200",
+	}
+	for title, want := range tests {
+		if got := CleanTitle(title); got != want {
+			t.Errorf("CleanTitle(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
+
+func TestSanitizeFileName(t *testing.T) {
+	tests := map[string]string{
+		"Valorant - Ace":       "Valorant - Ace",
+		`Ace: "clutch" 1/2?`:   "Ace clutch 12",
+		" ..hidden.. ":         "hidden",
+		"tab\tand\nnewline":    "tabandnewline",
+		`C:\Clips\<clip>*.mp4`: "CClipsclip.mp4",
+	}
+	for name, want := range tests {
+		if got := SanitizeFileName(name); got != want {
+			t.Errorf("SanitizeFileName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
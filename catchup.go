@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"autoclipsend/logger"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// MissedClip is a clip saved while AutoClipSend wasn't running
+type MissedClip struct {
+	FilePath   string    `json:"filePath"`
+	FileName   string    `json:"fileName"`
+	Size       int64     `json:"size"`
+	RecordedAt time.Time `json:"recordedAt"`
+	Sent       bool      `json:"sent"` // A copy was already sent, e.g. before a re-sync
+}
+
+// catchUp offers clips recorded since the last processed clip, as one batch
+func (a *App) catchUp() {
+	since := a.configManager.Snapshot().LastProcessedTime
+	if since.IsZero() {
+		// First run: don't offer the whole existing library
+		if err := a.configManager.SetLastProcessed(time.Now()); err != nil {
+			logger.Warn("Failed to save last processed time: %v", err)
+		}
+		return
+	}
+
+	clips := a.scanMissedClips(since)
+	if len(clips) == 0 {
+		logger.Info("No clips recorded since %s", since.Format(time.RFC3339))
+		return
+	}
+	logger.Info("Found %d clips recorded since %s", len(clips), since.Format(time.RFC3339))
+
+	a.missedMutex.Lock()
+	a.missedClips = clips
+	a.missedMutex.Unlock()
+	a.emitMissedClips()
+
+	message := "1 clip recorded while you were away"
+	if len(clips) > 1 {
+		message = fmt.Sprintf("%d clips recorded while you were away", len(clips))
+	}
+	if err := a.notificationHandler.SendSystemNotification("AutoClipSend", message); err != nil {
+		logger.Warn("Failed to show catch-up notification: %v", err)
+	}
+}
+
+// scanMissedClips lists video files in the monitored folders modified after since, oldest first
+func (a *App) scanMissedClips(since time.Time) []MissedClip {
+	var clips []MissedClip
+	seen := make(map[string]bool)
+
+	for _, watch := range a.activeWatches() {
+		root := watch.Path
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil // Skip unreadable entries, keep scanning the rest
+			}
+			if d.IsDir() {
+				if path != root && !watch.Entry.Recursive {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if seen[path] || !a.isClipCandidate(path) {
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil || !info.ModTime().After(since) {
+				return nil
+			}
+			if err := a.checkClipLimits(a.ctx, path); err != nil {
+				logger.Debug("Not offering missed clip %s: %v", path, err)
+				return nil
+			}
+			seen[path] = true
+			clips = append(clips, MissedClip{
+				FilePath:   path,
+				FileName:   d.Name(),
+				Size:       info.Size(),
+				RecordedAt: info.ModTime(),
+				Sent:       a.ledger.IsSent(path),
+			})
+			return nil
+		})
+	}
+
+	sort.Slice(clips, func(i, j int) bool {
+		return clips[i].RecordedAt.Before(clips[j].RecordedAt)
+	})
+	return clips
+}
+
+// markProcessed advances the catch-up marker past a clip that was offered.
+// While missed clips are still waiting the marker stays put, so they are
+// offered again if the app closes before the user gets to them.
+func (a *App) markProcessed(filePath string) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return
+	}
+
+	a.missedMutex.Lock()
+	if info.ModTime().After(a.latestProcessed) {
+		a.latestProcessed = info.ModTime()
+	}
+	waiting := len(a.missedClips) > 0
+	latest := a.latestProcessed
+	a.missedMutex.Unlock()
+
+	if waiting {
+		return
+	}
+	if err := a.configManager.SetLastProcessed(latest); err != nil {
+		logger.Warn("Failed to save last processed time: %v", err)
+	}
+}
+
+// GetMissedClips returns clips recorded while the app was closed that haven't been dealt with
+func (a *App) GetMissedClips() []MissedClip {
+	a.missedMutex.Lock()
+	defer a.missedMutex.Unlock()
+	return append([]MissedClip(nil), a.missedClips...)
+}
+
+// SendMissedClips queues the given missed clips for sending and removes them from the list
+func (a *App) SendMissedClips(filePaths []string, audioOnly bool) ([]SendJob, error) {
+	var jobs []SendJob
+	var sent []string
+	var firstErr error
+	for _, path := range filePaths {
+		job, err := a.QueueSend(path, "", audioOnly)
+		if err != nil {
+			logger.Error("Failed to queue missed clip %s: %v", path, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		jobs = append(jobs, job)
+		sent = append(sent, path)
+	}
+	a.removeMissedClips(sent)
+
+	if firstErr != nil && len(jobs) == 0 {
+		return nil, firstErr
+	}
+	return jobs, nil
+}
+
+// SkipMissedClips drops the given clips from the missed list without sending them
+func (a *App) SkipMissedClips(filePaths []string) {
+	a.removeMissedClips(filePaths)
+}
+
+// DismissMissedClips drops every remaining missed clip
+func (a *App) DismissMissedClips() {
+	a.missedMutex.Lock()
+	paths := make([]string, 0, len(a.missedClips))
+	for _, clip := range a.missedClips {
+		paths = append(paths, clip.FilePath)
+	}
+	a.missedMutex.Unlock()
+
+	a.removeMissedClips(paths)
+}
+
+// removeMissedClips takes clips off the missed list, saving the catch-up marker once it is empty
+func (a *App) removeMissedClips(filePaths []string) {
+	if len(filePaths) == 0 {
+		return
+	}
+	remove := make(map[string]bool, len(filePaths))
+	for _, path := range filePaths {
+		remove[path] = true
+	}
+
+	a.missedMutex.Lock()
+	remaining := a.missedClips[:0]
+	for _, clip := range a.missedClips {
+		if remove[clip.FilePath] {
+			if clip.RecordedAt.After(a.latestProcessed) {
+				a.latestProcessed = clip.RecordedAt
+			}
+			continue
+		}
+		remaining = append(remaining, clip)
+	}
+	a.missedClips = remaining
+	empty := len(remaining) == 0
+	latest := a.latestProcessed
+	a.missedMutex.Unlock()
+
+	a.emitMissedClips()
+	if empty {
+		if err := a.configManager.SetLastProcessed(latest); err != nil {
+			logger.Warn("Failed to save last processed time: %v", err)
+		}
+	}
+}
+
+// emitMissedClips tells the frontend the missed list changed
+func (a *App) emitMissedClips() {
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "missedClipsUpdated", a.GetMissedClips())
+	}
+}
@@ -0,0 +1,87 @@
+// Package clipfilter decides from a file's name and location whether it is
+// offered as a clip. It doesn't touch the disk, so it builds and is tested
+// on any OS.
+package clipfilter
+
+import (
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// videoExts are the file types offered as clips without any configuration
+var videoExts = []string{".mp4", ".avi", ".mov", ".mkv", ".wmv", ".flv", ".webm", ".m4v"}
+
+// Filter decides which files in a watched folder are offered as clips.
+// Zero values mean no restriction.
+type Filter struct {
+	Include         []string `json:"include"`          // Glob patterns, e.g. "*Replay*" or "Valorant/*"; if set a file must match one
+	Exclude         []string `json:"exclude"`          // Glob patterns that reject a file
+	ExtraExtensions []string `json:"extra_extensions"` // Offered on top of the built-in video types, e.g. ".ts"
+	MinSizeMB       float64  `json:"min_size_mb"`
+	MaxSizeMB       float64  `json:"max_size_mb"`
+	MinDuration     float64  `json:"min_duration"` // in seconds
+	MaxDuration     float64  `json:"max_duration"` // in seconds
+}
+
+// IsVideo reports whether filePath has one of the built-in video extensions
+func IsVideo(filePath string) bool {
+	return slices.Contains(videoExts, strings.ToLower(filepath.Ext(filePath)))
+}
+
+// Matches checks a file's name against the built-in video types and the
+// filter's extensions and patterns. rel is the path below the watched
+// folder, or empty if unknown, in which case folder patterns see the name.
+// It is cheap enough to run on every watcher event.
+func (f Filter) Matches(filePath, rel string) bool {
+	// Skip our own compressed output so sending doesn't trigger another prompt
+	if strings.Contains(filepath.Base(filePath), "_compressed") {
+		return false
+	}
+	if !IsVideo(filePath) && !hasExtension(filePath, f.ExtraExtensions) {
+		return false
+	}
+
+	name := strings.ToLower(filepath.Base(filePath))
+	rel = strings.ToLower(filepath.ToSlash(rel))
+	if rel == "" {
+		rel = name
+	}
+
+	if len(f.Include) > 0 && !matchesAny(f.Include, name, rel) {
+		return false
+	}
+	return !matchesAny(f.Exclude, name, rel)
+}
+
+// hasExtension reports whether filePath ends in one of exts, with or without the leading dot
+func hasExtension(filePath string, exts []string) bool {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	for _, e := range exts {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if e != "" && ext == "."+strings.TrimPrefix(e, ".") {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAny matches patterns against the file name, or the slash-separated
+// path below the watched folder for patterns containing a slash
+func matchesAny(patterns []string, name, rel string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(filepath.ToSlash(strings.TrimSpace(pattern)))
+		if pattern == "" {
+			continue
+		}
+		target := name
+		if strings.Contains(pattern, "/") {
+			target = rel
+		}
+		if ok, err := path.Match(pattern, target); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
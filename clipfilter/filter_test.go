@@ -0,0 +1,74 @@
+package clipfilter
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestMatchesAny(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		file     string // Lowercase name
+		rel      string // Lowercase slash-separated path below the watched folder
+		want     bool
+	}{
+		{"no patterns", nil, "clip.mp4", "clip.mp4", false},
+		{"blank patterns are skipped", []string{"", "  "}, "clip.mp4", "clip.mp4", false},
+		{"name pattern", []string{"*replay*"}, "replay_01.mp4", "valorant/replay_01.mp4", true},
+		{"name pattern ignores case", []string{"*Replay*"}, "replay_01.mp4", "replay_01.mp4", true},
+		{"name pattern doesn't see folders", []string{"valorant*"}, "clip.mp4", "valorant/clip.mp4", false},
+		{"folder pattern", []string{"Valorant/*"}, "clip.mp4", "valorant/clip.mp4", true},
+		{"folder pattern other folder", []string{"valorant/*"}, "clip.mp4", "apex/clip.mp4", false},
+		{"folder pattern is one level", []string{"valorant/*"}, "clip.mp4", "valorant/ranked/clip.mp4", false},
+		{"second pattern matches", []string{"*.mkv", "*.mp4"}, "clip.mp4", "clip.mp4", true},
+		{"bad pattern is ignored", []string{"[", "*.mp4"}, "clip.mp4", "clip.mp4", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesAny(tt.patterns, tt.file, tt.rel); got != tt.want {
+				t.Errorf("matchesAny(%q, %q, %q) = %v, want %v", tt.patterns, tt.file, tt.rel, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "Clips")
+	tests := []struct {
+		name   string
+		filter Filter
+		path   string
+		rel    string // Path below root, as the caller passes it
+		want   bool
+	}{
+		{"video without a filter", Filter{}, filepath.Join(root, "clip.mp4"), "clip.mp4", true},
+		{"not a video", Filter{}, filepath.Join(root, "notes.txt"), "notes.txt", false},
+		{"our compressed output", Filter{}, filepath.Join(root, "clip_compressed.mp4"), "clip_compressed.mp4", false},
+		{"extra extension", Filter{ExtraExtensions: []string{"ts"}}, filepath.Join(root, "clip.TS"), "clip.TS", true},
+		{"include by name", Filter{Include: []string{"*Replay*"}}, filepath.Join(root, "Replay 1.mp4"), "Replay 1.mp4", true},
+		{"not included", Filter{Include: []string{"*Replay*"}}, filepath.Join(root, "Desktop 1.mp4"), "Desktop 1.mp4", false},
+		{"include folder with capitals", Filter{Include: []string{"Valorant/*"}}, filepath.Join(root, "Valorant", "Clip 1.mp4"), filepath.Join("Valorant", "Clip 1.mp4"), true},
+		{"include folder other game", Filter{Include: []string{"Valorant/*"}}, filepath.Join(root, "Apex", "Clip 1.mp4"), filepath.Join("Apex", "Clip 1.mp4"), false},
+		{"exclude folder with capitals", Filter{Exclude: []string{"Desktop/*"}}, filepath.Join(root, "Desktop", "Clip 1.mp4"), filepath.Join("Desktop", "Clip 1.mp4"), false},
+		{"exclude by name", Filter{Exclude: []string{"*_temp*"}}, filepath.Join(root, "Clip_TEMP.mp4"), "Clip_TEMP.mp4", false},
+		{"unknown folder matches folder patterns on the name", Filter{Include: []string{"Valorant/*"}}, filepath.Join(root, "Valorant", "clip.mp4"), "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(tt.path, tt.rel); got != tt.want {
+				t.Errorf("Matches(%s, %q) = %v, want %v", tt.path, tt.rel, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsVideo(t *testing.T) {
+	for path, want := range map[string]bool{"clip.mp4": true, "CLIP.MKV": true, "clip.ts": false, "clip": false} {
+		if got := IsVideo(path); got != want {
+			t.Errorf("IsVideo(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
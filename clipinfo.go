@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"autoclipsend/logger"
+)
+
+// Clip sources, used for routing and reporting
+const (
+	SourceMedalTV = "medaltv"
+	SourceNVIDIA  = "nvidia"
+	SourceCustom  = "custom"
+	SourceOther   = "other" // Sent manually from a folder that isn't watched
+)
+
+// ClipInfo describes a clip about to be sent: where it came from and what we know about it
+type ClipInfo struct {
+	FilePath  string `json:"filePath"`
+	FileName  string `json:"fileName"`
+	Source    string `json:"source"`
+	WatchID   string `json:"watchId"`   // Watch entry whose folder holds the clip, "" if none
+	Subfolder string `json:"subfolder"` // Folder relative to the watched path, "" for the root
+	GameTitle string `json:"gameTitle"` // From Medal's clips.json, when available
+	Title     string `json:"title"`     // Medal content title, when available
+
+	// Filled in by probeClip
+	Duration   float64   `json:"duration"` // in seconds
+	Width      int       `json:"width"`
+	Height     int       `json:"height"`
+	Size       int64     `json:"size"` // Size of the original file in bytes
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+// describeClip gathers the source, subfolder and Medal metadata for a file
+func (a *App) describeClip(filePath string) ClipInfo {
+	info := ClipInfo{
+		FilePath: filePath,
+		FileName: filepath.Base(filePath),
+		Source:   SourceOther,
+	}
+
+	var root string
+	if entry, dir, ok := a.watchEntryFor(filePath); ok {
+		info.WatchID = entry.ID
+		info.Source = entry.Source
+		root = dir
+	} else if a.isMedalTVClip(filePath) {
+		info.Source = SourceMedalTV
+		root, _ = a.GetMedalTVClipFolder()
+	} else if a.isNVIDIAClip(filePath) {
+		info.Source = SourceNVIDIA
+		root, _ = a.GetNVIDIACurrentDirectory()
+	}
+
+	if root != "" {
+		if rel, ok := relativeTo(root, filepath.Dir(filePath)); ok && rel != "." {
+			info.Subfolder = rel
+		}
+	}
+
+	if stat, err := os.Stat(filePath); err == nil {
+		info.Size = stat.Size()
+		info.RecordedAt = stat.ModTime()
+	}
+
+	if clip, ok := a.findMedalTVClip(filePath); ok {
+		info.GameTitle = clip.GameTitle
+		info.Title = clip.Content.ContentTitle
+		if clip.TimeCreated > 0 {
+			info.RecordedAt = time.Unix(int64(clip.TimeCreated), 0)
+		}
+	}
+
+	return info
+}
+
+// probeClip fills in duration and resolution from the file that is actually sent
+func (a *App) probeClip(ctx context.Context, info *ClipInfo, path string) {
+	probe, err := a.transcoder.Probe(ctx, path)
+	if err != nil {
+		logger.Warn("Could not probe %s: %v", path, err)
+		return
+	}
+	info.Duration = probe.Duration
+	if video := probe.Video(); video != nil {
+		info.Width = video.Width
+		info.Height = video.Height
+	}
+}
+
+// isNVIDIAClip checks if a file is inside the NVIDIA capture folder
+func (a *App) isNVIDIAClip(filePath string) bool {
+	if !a.configManager.Snapshot().UseNVIDIAPath {
+		return false
+	}
+
+	nvidiaPath, err := a.GetNVIDIACurrentDirectory()
+	if err != nil {
+		return false
+	}
+
+	_, ok := relativeTo(nvidiaPath, filePath)
+	return ok
+}
+
+// findMedalTVClip looks a file up in Medal TV's clips.json
+func (a *App) findMedalTVClip(filePath string) (MedalTVClip, bool) {
+	clips, err := loadMedalTVClips()
+	if err != nil {
+		return MedalTVClip{}, false
+	}
+
+	for _, clip := range clips {
+		if clip.FilePath != "" && samePath(clip.FilePath, filePath) {
+			return clip, true
+		}
+	}
+	return MedalTVClip{}, false
+}
+
+// relativeTo returns path relative to root, if path is inside root. Folder
+// names are compared case-insensitively, as Windows does, and the relative
+// part keeps the original casing of path.
+func relativeTo(root, path string) (string, bool) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", false
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+
+	sep := string(filepath.Separator)
+	rootElems := strings.Split(strings.TrimSuffix(absRoot, sep), sep)
+	pathElems := strings.Split(strings.TrimSuffix(absPath, sep), sep)
+	if len(pathElems) < len(rootElems) {
+		return "", false
+	}
+	for i, elem := range rootElems {
+		if !strings.EqualFold(elem, pathElems[i]) {
+			return "", false
+		}
+	}
+	if len(pathElems) == len(rootElems) {
+		return ".", true
+	}
+	return filepath.Join(pathElems[len(rootElems):]...), true
+}
+
+// samePath compares two file paths the way Windows does
+func samePath(a, b string) bool {
+	return strings.EqualFold(filepath.Clean(a), filepath.Clean(b))
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
+	"sync"
 	"time"
 )
 
@@ -15,31 +17,70 @@ type Stats struct {
 	TotalSize      int64     `json:"total_size_bytes"`
 	StartTime      time.Time `json:"start_time"`
 	LastUpdateTime time.Time `json:"last_update_time"`
+
+	// Modification time of the newest clip that was offered, startup catch-up looks for anything newer
+	LastProcessedTime time.Time `json:"last_processed_time"`
 }
 
 // Config holds application configuration and statistics
 type Config struct {
 	// Settings
 	WebhookURL            string `json:"webhook_url"`
-	DiscordWebhook        string `json:"discord_webhook"` // Alternative name for webhook
-	MonitorPath           string `json:"monitor_path"`
+	DiscordWebhook        string `json:"discord_webhook"`        // Deprecated: migrated into WebhookURL on load
+	MonitorPath           string `json:"monitor_path"`           // Deprecated: mirrors the custom folder watch entry
 	MaxFileSize           int64  `json:"max_file_size"`          // in MB
 	CheckInterval         int    `json:"check_interval"`         // in seconds
 	StartupInitialization bool   `json:"startup_initialization"` // Whether to start monitoring on startup
 	WindowsStartup        bool   `json:"windows_startup"`        // Whether to start with Windows
-	RecursiveMonitoring   bool   `json:"recursive_monitoring"`   // Whether to monitor subfolders recursively
+	RecursiveMonitoring   bool   `json:"recursive_monitoring"`   // Deprecated: applied to the built-in watch entries when changed
 	DesktopShortcut       bool   `json:"desktop_shortcut"`       // Whether to create/maintain desktop shortcut
-	UseMedalTVPath        bool   `json:"use_medaltv_path"`       // Whether to use MedalTV's clipFolder path
-	UseNVIDIAPath         bool   `json:"use_nvidia_path"`        // Whether to use NVIDIA's currentDirectoryV2 path
-	UseCustomPath         bool   `json:"use_custom_path"`        // Whether to use a custom path selection
+	UseMedalTVPath        bool   `json:"use_medaltv_path"`       // Deprecated: mirrors the Medal watch entry
+	UseNVIDIAPath         bool   `json:"use_nvidia_path"`        // Deprecated: mirrors the NVIDIA watch entry
+	UseCustomPath         bool   `json:"use_custom_path"`        // Deprecated: mirrors the custom folder watch entry
+	SendWorkers           int    `json:"send_workers"`           // Number of clips sent in parallel (applied on restart)
+	UploadTimeout         int    `json:"upload_timeout"`         // in seconds, per upload attempt
+	DuplicatePolicy       string `json:"duplicate_policy"`       // DuplicateWarn (default), DuplicateRefuse or DuplicateAllow
+	UndoWindow            int    `json:"undo_window"`            // in seconds, how long UndoLastSend can take a send back
+	OversizeStrategy      string `json:"oversize_strategy"`      // OversizeCompress (default) or OversizeSplit for videos over MaxFileSize
+
+	// Folders watched for new clips. Network and FUSE mounts don't deliver
+	// change notifications and are polled unless an entry says otherwise.
+	Watches      []WatchEntry `json:"watches"`
+	PollInterval int          `json:"poll_interval"` // in seconds, for polled folders
+
+	// Destinations and the rules that route clips to them
+	Destinations []Destination `json:"destinations"`
+	Routes       []RouteRule   `json:"routes"`
+
+	// What to do with new clips instead of asking, first match wins
+	AutoSendRules []AutoSendRule `json:"auto_send_rules"`
+
+	// How the Discord message looks
+	EmbedEnabled     bool   `json:"embed_enabled"`      // Post clip details as a rich embed
+	EmbedColor       string `json:"embed_color"`        // Hex color such as #ff7c3d
+	WebhookUsername  string `json:"webhook_username"`   // Overrides the webhook's name if set
+	WebhookAvatarURL string `json:"webhook_avatar_url"` // Overrides the webhook's avatar if set
+	CaptionTemplate  string `json:"caption_template"`   // Default message, e.g. "{game} - {title}", "{title}" when empty
+	FilenameTemplate string `json:"filename_template"`  // Attachment name without extension, e.g. "{game}_{date}"
 
 	// Statistics
 	Stats
 }
 
-// ConfigManager handles saving and loading configuration
+// Defaults used when the config doesn't set these values
+const (
+	defaultSendWorkers   = 2
+	defaultUploadTimeout = 600 // seconds, large clips on slow connections need a while
+	defaultUndoWindow    = 120 // seconds
+)
+
+// ConfigManager owns the configuration and saves it on every change. Readers
+// take a Snapshot, so settings changed from the UI while clips are being sent
+// never race with them.
 type ConfigManager struct {
 	configPath string
+	mu         sync.RWMutex // Guards config, writers hold it while saving so changes are written in order
+	config     *Config
 }
 
 // NewConfigManager creates a new configuration manager
@@ -53,18 +94,83 @@ func NewConfigManager() *ConfigManager {
 	}
 }
 
-// SaveConfig saves the configuration to file
-func (cm *ConfigManager) SaveConfig(config *Config) error {
+// Snapshot returns a copy of the configuration that shares nothing with the live one
+func (cm *ConfigManager) Snapshot() Config {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.current()
+}
+
+// current copies the configuration, the caller holds cm.mu
+func (cm *ConfigManager) current() Config {
+	if cm.config == nil {
+		return Config{}
+	}
+	return cm.config.clone()
+}
+
+// Update applies change to a copy of the configuration, saves it and makes it
+// current, holding the lock throughout so changes from the UI, watchers and
+// send workers don't interleave. Nothing changes if change returns an error.
+func (cm *ConfigManager) Update(change func(*Config) error) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	config := cm.current()
+	if err := change(&config); err != nil {
+		return err
+	}
+	return cm.commit(&config)
+}
+
+// commit saves config and makes it current, the caller holds cm.mu
+func (cm *ConfigManager) commit(config *Config) error {
 	data, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
 		return err
 	}
+	if err := os.WriteFile(cm.configPath, data, 0644); err != nil {
+		return err
+	}
+	cm.config = config
+	return nil
+}
 
-	return os.WriteFile(cm.configPath, data, 0644)
+// clone returns a copy of c with its own slices
+func (c *Config) clone() Config {
+	copied := *c
+	copied.Watches = slices.Clone(c.Watches)
+	for i := range copied.Watches {
+		filter := &copied.Watches[i].Filter
+		filter.Include = slices.Clone(filter.Include)
+		filter.Exclude = slices.Clone(filter.Exclude)
+		filter.ExtraExtensions = slices.Clone(filter.ExtraExtensions)
+	}
+	copied.Destinations = slices.Clone(c.Destinations)
+	for i := range copied.Destinations {
+		copied.Destinations[i].ForumTags = slices.Clone(copied.Destinations[i].ForumTags)
+	}
+	copied.Routes = slices.Clone(c.Routes)
+	for i := range copied.Routes {
+		copied.Routes[i].Destinations = slices.Clone(copied.Routes[i].Destinations)
+	}
+	copied.AutoSendRules = slices.Clone(c.AutoSendRules)
+	return copied
 }
 
-// LoadConfig loads the configuration from file (legacy)
-func (cm *ConfigManager) LoadConfig() (*Config, error) {
+// LoadConfig loads the configuration from file and makes it current
+func (cm *ConfigManager) LoadConfig() (Config, error) {
+	config, err := cm.readConfig()
+	if err != nil {
+		return Config{}, err
+	}
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.config = config
+	return cm.current(), nil
+}
+
+// readConfig reads the configuration file, falling back to the defaults
+func (cm *ConfigManager) readConfig() (*Config, error) {
 	data, err := os.ReadFile(cm.configPath)
 	if err != nil { // Return default config if file doesn't exist
 		return &Config{
@@ -79,6 +185,8 @@ func (cm *ConfigManager) LoadConfig() (*Config, error) {
 			UseMedalTVPath:        false, // Default to disabled
 			UseNVIDIAPath:         false, // Default to disabled
 			UseCustomPath:         false, // Default to disabled
+			SendWorkers:           defaultSendWorkers,
+			UploadTimeout:         defaultUploadTimeout,
 			Stats: Stats{
 				TotalClips:     0,
 				SessionClips:   0,
@@ -90,6 +198,9 @@ func (cm *ConfigManager) LoadConfig() (*Config, error) {
 	}
 	var config Config
 	err = json.Unmarshal(data, &config)
+	if err == nil {
+		migrateConfig(&config)
+	}
 	if err != nil {
 		// Return default config if JSON parsing fails
 		return &Config{
@@ -104,6 +215,8 @@ func (cm *ConfigManager) LoadConfig() (*Config, error) {
 			UseMedalTVPath:        false, // Default to disabled
 			UseNVIDIAPath:         false, // Default to disabled
 			UseCustomPath:         false, // Default to disabled
+			SendWorkers:           defaultSendWorkers,
+			UploadTimeout:         defaultUploadTimeout,
 			Stats: Stats{
 				TotalClips:     0,
 				SessionClips:   0,
@@ -117,25 +230,55 @@ func (cm *ConfigManager) LoadConfig() (*Config, error) {
 	return &config, nil
 }
 
+// migrateConfig upgrades settings written by older versions
+func migrateConfig(config *Config) {
+	// DiscordWebhook was an unused alias of WebhookURL
+	if config.WebhookURL == "" && config.DiscordWebhook != "" {
+		config.WebhookURL = config.DiscordWebhook
+	}
+	config.DiscordWebhook = ""
+
+	// MonitorPath and the MedalTV/NVIDIA toggles became watch entries
+	if config.Watches == nil {
+		config.Watches = legacyWatchEntries(config)
+	}
+}
+
 // IncrementClipCount increments the clip counters and updates file size
-func (cm *ConfigManager) IncrementClipCount(config *Config, fileSize int64) error {
-	config.TotalClips++
-	config.SessionClips++
-	config.TotalSize += fileSize
-	config.LastClipTime = time.Now()
-	config.LastUpdateTime = time.Now()
-	return cm.SaveConfig(config)
+func (cm *ConfigManager) IncrementClipCount(fileSize int64) error {
+	return cm.Update(func(c *Config) error {
+		c.TotalClips++
+		c.SessionClips++
+		c.TotalSize += fileSize
+		c.LastClipTime = time.Now()
+		c.LastUpdateTime = time.Now()
+		return nil
+	})
+}
+
+// SetLastProcessed records t as the newest clip time that has been dealt with
+func (cm *ConfigManager) SetLastProcessed(t time.Time) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	config := cm.current()
+	if !t.After(config.LastProcessedTime) {
+		return nil
+	}
+	config.LastProcessedTime = t
+	return cm.commit(&config)
 }
 
 // ResetSessionStats resets session-specific statistics
-func (cm *ConfigManager) ResetSessionStats(config *Config) error {
-	config.SessionClips = 0
-	config.StartTime = time.Now()
-	config.LastUpdateTime = time.Now()
-	return cm.SaveConfig(config)
+func (cm *ConfigManager) ResetSessionStats() error {
+	return cm.Update(func(c *Config) error {
+		c.SessionClips = 0
+		c.StartTime = time.Now()
+		c.LastUpdateTime = time.Now()
+		return nil
+	})
 }
 
 // GetUptime returns the uptime since start time
-func (cm *ConfigManager) GetUptime(config *Config) time.Duration {
-	return time.Since(config.StartTime)
+func (cm *ConfigManager) GetUptime() time.Duration {
+	return time.Since(cm.Snapshot().StartTime)
 }
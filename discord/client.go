@@ -0,0 +1,239 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptrace"
+	"sync/atomic"
+	"time"
+)
+
+// Payload is the JSON part of a webhook message
+type Payload struct {
+	Content     string   `json:"content,omitempty"`
+	Username    string   `json:"username,omitempty"`   // Overrides the webhook's default name
+	AvatarURL   string   `json:"avatar_url,omitempty"` // Overrides the webhook's default avatar
+	Embeds      []Embed  `json:"embeds,omitempty"`
+	ThreadName  string   `json:"thread_name,omitempty"`  // Starts a new post when the webhook belongs to a forum channel
+	AppliedTags []string `json:"applied_tags,omitempty"` // Forum tag IDs for a new post
+}
+
+// isEmpty reports whether the payload has nothing worth sending
+func (p Payload) isEmpty() bool {
+	return p.Content == "" && p.Username == "" && p.AvatarURL == "" && len(p.Embeds) == 0 &&
+		p.ThreadName == "" && len(p.AppliedTags) == 0
+}
+
+// RetryFunc is called before a failed request is retried
+type RetryFunc func(attempt int, wait time.Duration, err error)
+
+// MaxAttachments is the most files Discord accepts in one message
+const MaxAttachments = 10
+
+// File is an extra attachment of an upload
+type File struct {
+	Path string
+	Name string // Name shown in Discord, defaults to the file's own name
+}
+
+// UploadRequest describes a file upload to a webhook. Files are attached
+// after FilePath, up to MaxAttachments in total.
+type UploadRequest struct {
+	FilePath   string
+	FileName   string // Name shown in Discord, defaults to the file's own name
+	Files      []File
+	ThreadID   string // Posts into this existing thread or forum post instead of the channel
+	Payload    Payload
+	Timeout    time.Duration // Per attempt, 0 means only ctx limits the upload
+	OnRetry    RetryFunc     // Optional, called before each retry
+	OnProgress ProgressFunc  // Optional, called as the body is sent
+}
+
+// Client sends files to Discord webhooks while honoring rate limits
+type Client struct {
+	httpClient  *http.Client
+	limiter     *rateLimiter
+	MaxAttempts int           // Total attempts per request, including the first
+	BaseBackoff time.Duration // Delay before the first retry of a transient failure
+	MaxBackoff  time.Duration // Upper bound for the exponential backoff
+}
+
+// NewClient creates a webhook client. Requests are bounded by their context
+// and the per-request timeout rather than a client-wide one.
+func NewClient() *Client {
+	return &Client{
+		httpClient:  &http.Client{},
+		limiter:     newRateLimiter(),
+		MaxAttempts: 5,
+		BaseBackoff: time.Second,
+		MaxBackoff:  30 * time.Second,
+	}
+}
+
+// Upload streams the files to the webhook and returns the message Discord
+// created. Posts are only retried when Discord can't have created the message,
+// other failures come back with Error.MaybeSent set.
+func (c *Client) Upload(ctx context.Context, webhookURL string, req UploadRequest) (*Message, error) {
+	var payloadJSON []byte
+	if !req.Payload.isEmpty() {
+		var err error
+		if payloadJSON, err = json.Marshal(req.Payload); err != nil {
+			return nil, fmt.Errorf("error encoding payload: %w", err)
+		}
+	}
+
+	files := []attachment{{path: req.FilePath, name: req.FileName}}
+	for _, f := range req.Files {
+		files = append(files, attachment{path: f.Path, name: f.Name})
+	}
+	if len(files) > MaxAttachments {
+		return nil, fmt.Errorf("too many attachments: %d (limit %d)", len(files), MaxAttachments)
+	}
+
+	body, err := newMultipartBody(payloadJSON, files)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := withWait(InThread(webhookURL, req.ThreadID))
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := c.execute(ctx, webhookURL, req.Timeout, req.OnRetry, false, func(attemptCtx context.Context) (*http.Request, error) {
+		reader, err := body.open(req.OnProgress)
+		if err != nil {
+			return nil, err
+		}
+		r, err := http.NewRequestWithContext(attemptCtx, http.MethodPost, target, reader)
+		if err != nil {
+			reader.Close()
+			return nil, err
+		}
+		r.ContentLength = body.length
+		r.Header.Set("Content-Type", body.contentType)
+		return r, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The file is posted at this point, but without the message it can't be edited or deleted later
+	msg, err := parseMessage(respBody)
+	if err != nil {
+		return nil, &Error{Kind: KindTransient, MaybeSent: true, Message: "unreadable reply to the upload", Err: err}
+	}
+	return msg, nil
+}
+
+// execute runs the request built by newRequest until it succeeds, fails
+// permanently or runs out of attempts. key identifies the rate limit bucket
+// and timeout, if set, bounds each attempt. Requests that aren't idempotent
+// are only retried after a 429 or when they never left this machine.
+func (c *Client) execute(ctx context.Context, key string, timeout time.Duration, onRetry RetryFunc, idempotent bool, newRequest func(ctx context.Context) (*http.Request, error)) ([]byte, error) {
+	var lastErr *Error
+	for attempt := 1; attempt <= c.MaxAttempts; attempt++ {
+		if err := c.limiter.wait(ctx, key); err != nil {
+			return nil, err
+		}
+
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if timeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, timeout)
+		}
+
+		req, err := newRequest(attemptCtx)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+
+		body, wait, sent, err := c.attempt(req, key)
+		cancel()
+		if err == nil {
+			return body, nil
+		}
+
+		// Cancellation is not a Discord failure, hand it back untouched
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		var de *Error
+		if !errors.As(err, &de) {
+			return nil, err
+		}
+		de.Attempts = attempt
+		// Discord may have acted on a post before the reply got lost or failed,
+		// e.g. a 502 from its proxy after the message was created
+		if !idempotent && sent && de.Kind == KindTransient {
+			de.MaybeSent = true
+		}
+		lastErr = de
+		if !de.Retryable() || attempt == c.MaxAttempts {
+			break
+		}
+
+		if de.Kind != KindRateLimited {
+			wait = c.backoff(attempt)
+		}
+		if onRetry != nil {
+			onRetry(attempt, wait, de)
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+	return nil, lastErr
+}
+
+// attempt performs a single HTTP round trip. On a 429 it returns how long to
+// wait. sent reports whether the whole request was written, so Discord may
+// have acted on it.
+func (c *Client) attempt(req *http.Request, key string) (body []byte, wait time.Duration, sent bool, err error) {
+	var wrote atomic.Bool
+	trace := &httptrace.ClientTrace{
+		WroteRequest: func(info httptrace.WroteRequestInfo) { wrote.Store(info.Err == nil) },
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// Timeouts, connection resets and DNS hiccups are all worth retrying,
+		// as long as the request didn't get through
+		return nil, 0, wrote.Load(), &Error{Kind: KindTransient, Message: "request failed", Err: err}
+	}
+	defer resp.Body.Close()
+
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, true, &Error{Kind: KindTransient, StatusCode: resp.StatusCode, Message: "error reading response", Err: err}
+	}
+
+	c.limiter.update(key, resp.Header)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return body, 0, true, nil
+	}
+
+	apiErr := errorForStatus(resp.StatusCode, body)
+	if resp.StatusCode == http.StatusTooManyRequests {
+		wait, global := retryAfter(resp.Header, body)
+		c.limiter.block(key, wait, global)
+		return nil, wait, true, apiErr
+	}
+	return nil, 0, true, apiErr
+}
+
+// backoff returns the exponential delay, with jitter, before retry number attempt
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.BaseBackoff << (attempt - 1)
+	if d <= 0 || d > c.MaxBackoff {
+		d = c.MaxBackoff
+	}
+	// Up to 25% jitter so several failed uploads don't retry in lockstep
+	return d - time.Duration(rand.Int63n(int64(d)/4+1))
+}
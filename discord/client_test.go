@@ -0,0 +1,132 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestFile writes a small attachment and returns its path
+func newTestFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "clip.mp4")
+	if err := os.WriteFile(path, []byte("not really a video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func newTestClient() *Client {
+	c := NewClient()
+	c.BaseBackoff = time.Millisecond
+	c.MaxBackoff = time.Millisecond
+	return c
+}
+
+func TestUploadRetries(t *testing.T) {
+	tests := []struct {
+		name          string
+		replies       []int // Status of each reply in turn, the last one repeats
+		body          string
+		wantCalls     int32
+		wantKind      ErrorKind // Empty for success
+		wantMaybeSent bool
+	}{
+		{"success", []int{200}, `{"id":"1"}`, 1, "", false},
+		{"rate limited then success", []int{429, 200}, `{"id":"1","retry_after":0.001}`, 2, "", false},
+		{"bad gateway is not retried", []int{502}, ``, 1, KindTransient, true},
+		{"server error is not retried", []int{500, 200}, ``, 1, KindTransient, true},
+		{"webhook deleted", []int{404}, `{"message":"Unknown Webhook","code":10015}`, 1, KindNotFound, false},
+		{"unreadable reply", []int{200}, `<html>`, 1, KindTransient, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				io.Copy(io.Discard, r.Body)
+				n := int(calls.Add(1))
+				status := tt.replies[min(n, len(tt.replies))-1]
+				w.WriteHeader(status)
+				io.WriteString(w, tt.body)
+			}))
+			defer server.Close()
+
+			msg, err := newTestClient().Upload(context.Background(), server.URL, UploadRequest{FilePath: newTestFile(t)})
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("server saw %d requests, want %d", got, tt.wantCalls)
+			}
+			if tt.wantKind == "" {
+				if err != nil {
+					t.Fatalf("Upload() error = %v", err)
+				}
+				if msg.ID != "1" {
+					t.Errorf("message ID = %q, want 1", msg.ID)
+				}
+				return
+			}
+			de, ok := AsError(err)
+			if !ok {
+				t.Fatalf("Upload() error = %v, want a *discord.Error", err)
+			}
+			if de.Kind != tt.wantKind || de.MaybeSent != tt.wantMaybeSent {
+				t.Errorf("error kind %s, maybeSent %v, want %s, %v", de.Kind, de.MaybeSent, tt.wantKind, tt.wantMaybeSent)
+			}
+			if de.MaybeSent && de.Retryable() {
+				t.Error("an upload that may have been posted is reported as retryable")
+			}
+		})
+	}
+}
+
+func TestUploadRetriesUnsentRequest(t *testing.T) {
+	// Nothing listens here, so the upload never leaves this machine
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	c := newTestClient()
+	c.MaxAttempts = 3
+	var retries int
+	_, err := c.Upload(context.Background(), url, UploadRequest{
+		FilePath: newTestFile(t),
+		OnRetry:  func(int, time.Duration, error) { retries++ },
+	})
+	de, ok := AsError(err)
+	if !ok || !errors.Is(err, ErrTransient) {
+		t.Fatalf("Upload() error = %v, want a transient error", err)
+	}
+	if de.MaybeSent {
+		t.Error("a request that was never sent is marked as maybe sent")
+	}
+	if retries != 2 {
+		t.Errorf("retried %d times, want 2", retries)
+	}
+}
+
+func TestEditMessageRetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		io.WriteString(w, `{"id":"1"}`)
+	}))
+	defer server.Close()
+
+	content := "edited"
+	if _, err := newTestClient().EditMessage(context.Background(), server.URL, "1", MessageEdit{Content: &content}); err != nil {
+		t.Fatalf("EditMessage() error = %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("server saw %d requests, want 2", got)
+	}
+}
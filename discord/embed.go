@@ -0,0 +1,60 @@
+package discord
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Embed is a rich message block, see https://discord.com/developers/docs/resources/message#embed-object
+type Embed struct {
+	Title       string       `json:"title,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Color       int          `json:"color,omitempty"`
+	Timestamp   string       `json:"timestamp,omitempty"` // ISO 8601
+	Fields      []EmbedField `json:"fields,omitempty"`
+	Footer      *EmbedFooter `json:"footer,omitempty"`
+}
+
+// EmbedField is a name/value pair shown inside an embed
+type EmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+// EmbedFooter is the small text at the bottom of an embed
+type EmbedFooter struct {
+	Text string `json:"text"`
+}
+
+// Embed text limits enforced by Discord
+const (
+	maxEmbedTitle      = 256
+	maxEmbedFieldValue = 1024
+)
+
+// AddField appends an inline field, skipping empty values
+func (e *Embed) AddField(name, value string) {
+	if value == "" {
+		return
+	}
+	e.Fields = append(e.Fields, EmbedField{Name: name, Value: truncate(value, maxEmbedFieldValue), Inline: true})
+}
+
+// SetTitle sets the title, shortened to Discord's limit
+func (e *Embed) SetTitle(title string) {
+	e.Title = truncate(title, maxEmbedTitle)
+}
+
+// ParseColor converts "#ff7c3d" or "ff7c3d" into an embed color
+func ParseColor(hex string) (int, bool) {
+	hex = strings.TrimPrefix(strings.TrimSpace(hex), "#")
+	if len(hex) != 6 {
+		return 0, false
+	}
+	v, err := strconv.ParseInt(hex, 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	return int(v), true
+}
@@ -0,0 +1,122 @@
+package discord
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrorKind classifies why a webhook request failed
+type ErrorKind string
+
+const (
+	KindRateLimited  ErrorKind = "rate_limited" // Still rate limited after every retry
+	KindTransient    ErrorKind = "transient"    // 5xx, timeouts, connection resets
+	KindUnauthorized ErrorKind = "unauthorized" // 401/403, webhook token is invalid
+	KindNotFound     ErrorKind = "not_found"    // 404, webhook was deleted
+	KindTooLarge     ErrorKind = "too_large"    // 413, attachment exceeds the upload limit
+	KindBadRequest   ErrorKind = "bad_request"  // Any other rejected request
+)
+
+// Sentinel errors for use with errors.Is
+var (
+	ErrRateLimited  = &Error{Kind: KindRateLimited}
+	ErrTransient    = &Error{Kind: KindTransient}
+	ErrUnauthorized = &Error{Kind: KindUnauthorized}
+	ErrNotFound     = &Error{Kind: KindNotFound}
+	ErrTooLarge     = &Error{Kind: KindTooLarge}
+	ErrBadRequest   = &Error{Kind: KindBadRequest}
+)
+
+// Error is returned by Client whenever a webhook request does not succeed
+type Error struct {
+	Kind       ErrorKind `json:"kind"`
+	StatusCode int       `json:"statusCode,omitempty"` // HTTP status, 0 for transport errors
+	Code       int       `json:"code,omitempty"`       // Discord JSON error code, if any
+	Message    string    `json:"message"`
+	Attempts   int       `json:"attempts"`
+	MaybeSent  bool      `json:"maybeSent,omitempty"` // A post reached Discord and may have been created anyway
+	Err        error     `json:"-"`                   // Underlying transport error, if any
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if msg == "" && e.Err != nil {
+		msg = e.Err.Error()
+	}
+	if e.MaybeSent {
+		msg += " (the message may have been posted anyway, check the channel before retrying)"
+	}
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("discord %s error (HTTP %d): %s", e.Kind, e.StatusCode, msg)
+	}
+	return fmt.Sprintf("discord %s error: %s", e.Kind, msg)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches any Error of the same kind, so errors.Is(err, ErrNotFound) works
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Kind == e.Kind
+}
+
+// Retryable reports whether sending the same request again is safe and may
+// succeed. A post that may already exist is left for the caller to check.
+func (e *Error) Retryable() bool {
+	return (e.Kind == KindTransient && !e.MaybeSent) || e.Kind == KindRateLimited
+}
+
+// AsError extracts an *Error from err, if there is one
+func AsError(err error) (*Error, bool) {
+	var de *Error
+	if errors.As(err, &de) {
+		return de, true
+	}
+	return nil, false
+}
+
+// errorForStatus builds the Error for a non-success HTTP response
+func errorForStatus(status int, body []byte) *Error {
+	e := &Error{StatusCode: status}
+	var apiErr struct {
+		Message string `json:"message"`
+		Code    int    `json:"code"`
+	}
+	if json.Unmarshal(body, &apiErr) == nil && apiErr.Message != "" {
+		e.Message = apiErr.Message
+		e.Code = apiErr.Code
+	} else {
+		e.Message = truncate(string(body), 200)
+	}
+
+	switch {
+	case status == 429:
+		e.Kind = KindRateLimited
+	case status >= 500:
+		e.Kind = KindTransient
+	case status == 401 || status == 403:
+		e.Kind = KindUnauthorized
+	case status == 404:
+		e.Kind = KindNotFound
+	case status == 413:
+		e.Kind = KindTooLarge
+	default:
+		e.Kind = KindBadRequest
+	}
+	if e.Message == "" {
+		e.Message = fmt.Sprintf("unexpected status %d", status)
+	}
+	return e
+}
+
+// truncate shortens s to at most n characters, marking the cut with an ellipsis
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
@@ -0,0 +1,131 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// messageTimeout bounds edits and deletes, which carry no file
+const messageTimeout = 30 * time.Second
+
+// Message is the part of a posted webhook message the app keeps
+type Message struct {
+	ID        string  `json:"id"`
+	ChannelID string  `json:"channel_id"`
+	Content   string  `json:"content"`
+	Embeds    []Embed `json:"embeds"`
+}
+
+// MessageEdit is the body of a message edit. Nil fields are left unchanged.
+type MessageEdit struct {
+	Content *string `json:"content,omitempty"`
+	Embeds  []Embed `json:"embeds,omitempty"`
+}
+
+// withWait asks Discord to answer an execute request with the created message
+func withWait(webhookURL string) (string, error) {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	q := u.Query()
+	q.Set("wait", "true")
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// InThread points a webhook URL at a thread, so uploads, edits and deletes
+// apply to messages inside it. An empty threadID returns the URL unchanged.
+func InThread(webhookURL, threadID string) string {
+	if threadID == "" {
+		return webhookURL
+	}
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return webhookURL // Reported by the request that uses it
+	}
+	q := u.Query()
+	q.Set("thread_id", threadID)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// messageURL returns the endpoint of a message posted through the webhook,
+// keeping any query such as thread_id
+func messageURL(webhookURL, messageID string) (string, error) {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	q := u.Query()
+	q.Del("wait")
+	u.RawQuery = q.Encode()
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/messages/" + url.PathEscape(messageID)
+	return u.String(), nil
+}
+
+// parseMessage decodes the message Discord returned for a wait=true request
+func parseMessage(body []byte) (*Message, error) {
+	var msg Message
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, fmt.Errorf("error decoding message: %w", err)
+	}
+	return &msg, nil
+}
+
+// GetMessage fetches a message posted through the webhook
+func (c *Client) GetMessage(ctx context.Context, webhookURL, messageID string) (*Message, error) {
+	target, err := messageURL(webhookURL, messageID)
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.execute(ctx, webhookURL, messageTimeout, nil, true, func(attemptCtx context.Context) (*http.Request, error) {
+		return http.NewRequestWithContext(attemptCtx, http.MethodGet, target, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseMessage(body)
+}
+
+// EditMessage changes the text or embeds of a message posted through the webhook
+func (c *Client) EditMessage(ctx context.Context, webhookURL, messageID string, edit MessageEdit) (*Message, error) {
+	target, err := messageURL(webhookURL, messageID)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(edit)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding edit: %w", err)
+	}
+	body, err := c.execute(ctx, webhookURL, messageTimeout, nil, true, func(attemptCtx context.Context) (*http.Request, error) {
+		r, err := http.NewRequestWithContext(attemptCtx, http.MethodPatch, target, bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		r.Header.Set("Content-Type", "application/json")
+		return r, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseMessage(body)
+}
+
+// DeleteMessage removes a message posted through the webhook
+func (c *Client) DeleteMessage(ctx context.Context, webhookURL, messageID string) error {
+	target, err := messageURL(webhookURL, messageID)
+	if err != nil {
+		return err
+	}
+	_, err = c.execute(ctx, webhookURL, messageTimeout, nil, true, func(attemptCtx context.Context) (*http.Request, error) {
+		return http.NewRequestWithContext(attemptCtx, http.MethodDelete, target, nil)
+	})
+	return err
+}
@@ -0,0 +1,134 @@
+package discord
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// attachment is a file on disk and the name Discord should show for it
+type attachment struct {
+	path string
+	name string // Defaults to the base name of path
+}
+
+// ProgressFunc receives the number of body bytes sent so far and the total
+type ProgressFunc func(sent, total int64)
+
+// multipartBody is a multipart/form-data body that streams its files from
+// disk instead of holding them in memory. It can be opened once per attempt.
+type multipartBody struct {
+	contentType string
+	length      int64
+	headers     [][]byte // Part headers written before each file
+	trailer     []byte   // Closing boundary
+	files       []attachment
+}
+
+// newMultipartBody lays out a body with an optional payload_json field
+// followed by one part per file. Only the small framing is kept in memory.
+func newMultipartBody(payloadJSON []byte, files []attachment) (*multipartBody, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	body := &multipartBody{
+		contentType: writer.FormDataContentType(),
+		files:       files,
+	}
+
+	if len(payloadJSON) > 0 {
+		if err := writer.WriteField("payload_json", string(payloadJSON)); err != nil {
+			return nil, fmt.Errorf("error writing payload: %w", err)
+		}
+	}
+
+	for i, file := range files {
+		info, err := os.Stat(file.path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading file: %w", err)
+		}
+
+		field := "file"
+		if len(files) > 1 {
+			field = fmt.Sprintf("files[%d]", i)
+		}
+		name := file.name
+		if name == "" {
+			name = filepath.Base(file.path)
+		}
+		if _, err := writer.CreateFormFile(field, name); err != nil {
+			return nil, fmt.Errorf("error creating form file: %w", err)
+		}
+
+		body.headers = append(body.headers, append([]byte(nil), buf.Bytes()...))
+		body.length += int64(buf.Len()) + info.Size()
+		buf.Reset()
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("error closing multipart writer: %w", err)
+	}
+	body.trailer = append([]byte(nil), buf.Bytes()...)
+	body.length += int64(len(body.trailer))
+
+	return body, nil
+}
+
+// open returns a fresh reader over the whole body
+func (b *multipartBody) open(onProgress ProgressFunc) (io.ReadCloser, error) {
+	readers := make([]io.Reader, 0, len(b.files)*2+1)
+	opened := make([]*os.File, 0, len(b.files))
+	for i, file := range b.files {
+		f, err := os.Open(file.path)
+		if err != nil {
+			closeAll(opened)
+			return nil, fmt.Errorf("error opening file: %w", err)
+		}
+		opened = append(opened, f)
+		readers = append(readers, bytes.NewReader(b.headers[i]), f)
+	}
+	readers = append(readers, bytes.NewReader(b.trailer))
+
+	return &progressReader{
+		reader:     io.MultiReader(readers...),
+		files:      opened,
+		total:      b.length,
+		onProgress: onProgress,
+	}, nil
+}
+
+// progressReader counts bytes as the HTTP client consumes the body
+type progressReader struct {
+	reader     io.Reader
+	files      []*os.File
+	sent       int64
+	reported   int64
+	total      int64
+	onProgress ProgressFunc
+	closeOnce  sync.Once
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.sent += int64(n)
+	// Report roughly every 1% so a large upload doesn't flood the frontend
+	if r.onProgress != nil && n > 0 && (r.sent-r.reported >= r.total/100 || r.sent == r.total) {
+		r.reported = r.sent
+		r.onProgress(r.sent, r.total)
+	}
+	return n, err
+}
+
+func (r *progressReader) Close() error {
+	r.closeOnce.Do(func() { closeAll(r.files) })
+	return nil
+}
+
+func closeAll(files []*os.File) {
+	for _, f := range files {
+		f.Close()
+	}
+}
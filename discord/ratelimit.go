@@ -0,0 +1,98 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// rateLimiter remembers, per webhook, when the next request is allowed
+type rateLimiter struct {
+	mu           sync.Mutex
+	blockedUntil map[string]time.Time
+	globalUntil  time.Time
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{blockedUntil: make(map[string]time.Time)}
+}
+
+// wait blocks until the webhook identified by key may be called again
+func (r *rateLimiter) wait(ctx context.Context, key string) error {
+	r.mu.Lock()
+	until := r.blockedUntil[key]
+	if r.globalUntil.After(until) {
+		until = r.globalUntil
+	}
+	r.mu.Unlock()
+
+	delay := time.Until(until)
+	if delay <= 0 {
+		return nil
+	}
+	return sleep(ctx, delay)
+}
+
+// update records the X-RateLimit-* headers of a response
+func (r *rateLimiter) update(key string, h http.Header) {
+	if h.Get("X-RateLimit-Remaining") != "0" {
+		return
+	}
+	resetAfter, err := strconv.ParseFloat(h.Get("X-RateLimit-Reset-After"), 64)
+	if err != nil || resetAfter <= 0 {
+		return
+	}
+	r.block(key, secondsToDuration(resetAfter), false)
+}
+
+// block prevents requests for d, for one webhook or for every webhook
+func (r *rateLimiter) block(key string, d time.Duration, global bool) {
+	until := time.Now().Add(d)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if global {
+		if until.After(r.globalUntil) {
+			r.globalUntil = until
+		}
+		return
+	}
+	if until.After(r.blockedUntil[key]) {
+		r.blockedUntil[key] = until
+	}
+}
+
+// retryAfter reads how long a 429 response asks us to wait
+func retryAfter(h http.Header, body []byte) (time.Duration, bool) {
+	var payload struct {
+		RetryAfter float64 `json:"retry_after"`
+		Global     bool    `json:"global"`
+	}
+	if json.Unmarshal(body, &payload) == nil && payload.RetryAfter > 0 {
+		return secondsToDuration(payload.RetryAfter), payload.Global || h.Get("X-RateLimit-Global") == "true"
+	}
+	if secs, err := strconv.ParseFloat(h.Get("Retry-After"), 64); err == nil && secs > 0 {
+		return secondsToDuration(secs), h.Get("X-RateLimit-Global") == "true"
+	}
+	// Discord always sends one of the above, but never retry in a tight loop
+	return time.Second, false
+}
+
+func secondsToDuration(secs float64) time.Duration {
+	return time.Duration(secs * float64(time.Second))
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,160 @@
+package encode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"autoclipsend/logger"
+	"autoclipsend/media"
+)
+
+// Progress is how far ToSize has got
+type Progress struct {
+	Label    string        // What is running, e.g. "Compressing again to fit the size limit"
+	Duration float64       // Clip length in seconds
+	Pass     int           // Zero-based index of the ffmpeg run in progress
+	Passes   int           // Number of ffmpeg runs over the full clip in this attempt
+	OutTime  time.Duration // How much of the clip the current run has encoded
+	Speed    float64       // Encode speed as a multiple of real time, 0 if unknown
+}
+
+// ProgressFunc receives progress reports, it may be nil
+type ProgressFunc func(Progress)
+
+// ToSize plans a bitrate from the clip's duration and encodes input to output
+// with two-pass libx264 to land just under maxBytes. If the result still
+// overshoots, the target is reduced by the miss and re-planned. A clip whose
+// duration can't be read gets a fixed low-quality encode instead. It returns
+// a short description of the settings that produced output.
+func ToSize(ctx context.Context, t media.Transcoder, input, output string, maxBytes int64, progress ProgressFunc) (string, error) {
+	info, err := t.Probe(ctx, input)
+	if err != nil || info.Duration <= 0 {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		logger.Warn("Could not get video duration, using default compression: %v", err)
+		if err := fallback(ctx, t, input, output); err != nil {
+			return "", err
+		}
+		return "fallback: CRF 40, half size, 15fps", nil
+	}
+
+	targetBytes := maxBytes
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		plan, err := NewPlan(info, targetBytes)
+		if err != nil {
+			return "", err
+		}
+
+		logger.Info("Compression attempt %d: %s", attempt, plan)
+		state := Progress{Label: "Compressing", Duration: info.Duration, Passes: 2}
+		if attempt > 1 {
+			state.Label = "Compressing again to fit the size limit"
+		}
+		if progress != nil {
+			progress(state)
+		}
+
+		if err := twoPass(ctx, t, input, output, plan, state, progress); err != nil {
+			os.Remove(output)
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			logger.Error("Two-pass encode failed: %v", err)
+			return "", errors.New("video compression failed")
+		}
+
+		fileInfo, err := os.Stat(output)
+		if err != nil {
+			return "", err
+		}
+		if fileInfo.Size() <= maxBytes {
+			originalInfo, _ := os.Stat(input)
+			compressionRatio := float64(fileInfo.Size()) / float64(originalInfo.Size()) * 100
+			logger.Info("Video compressed successfully (%s), size: %d bytes (%.1f%% of original)",
+				plan, fileInfo.Size(), compressionRatio)
+			return fmt.Sprintf("two-pass %s (attempt %d)", plan, attempt), nil
+		}
+
+		// Overshot: shrink the budget by the size of the miss and plan again
+		logger.Warn("Encode overshot the limit: %d bytes (limit: %d bytes)", fileInfo.Size(), maxBytes)
+		targetBytes = shrinkTarget(targetBytes, fileInfo.Size(), maxBytes)
+	}
+
+	os.Remove(output)
+	return "", errors.New("could not compress video to target size")
+}
+
+// twoPass runs a libx264 two-pass encode of input following plan, reporting
+// both passes through progress starting from state
+func twoPass(ctx context.Context, t media.Transcoder, input, output string, plan Plan, state Progress, progress ProgressFunc) error {
+	passLog := strings.TrimSuffix(output, filepath.Ext(output)) + "_passlog"
+	defer func() {
+		// x264 writes <prefix>-0.log and <prefix>-0.log.mbtree
+		matches, _ := filepath.Glob(passLog + "*")
+		for _, m := range matches {
+			os.Remove(m)
+		}
+	}()
+
+	opts := media.VideoOptions{
+		Codec:       "libx264",
+		Preset:      "medium",
+		Bitrate:     plan.VideoBitrate,
+		PassLogFile: passLog,
+		Height:      plan.Height,
+		FPS:         plan.FPS,
+		FastStart:   true,
+	}
+	if plan.AudioBitrate > 0 {
+		opts.Audio = &media.AudioOptions{Codec: "aac", Bitrate: plan.AudioBitrate}
+	}
+
+	// A nil progress encodes without reporting
+	var report media.ProgressFunc
+	if progress != nil {
+		report = func(outTime time.Duration, speed float64) {
+			state.OutTime, state.Speed = outTime, speed
+			progress(state)
+		}
+	}
+
+	// Pass 1 only analyses the video, its output is thrown away
+	opts.Pass = 1
+	if err := t.EncodeVideo(ctx, input, output, opts, report); err != nil {
+		return fmt.Errorf("pass 1: %w", err)
+	}
+
+	opts.Pass = 2
+	state.Pass = 1
+	if err := t.EncodeVideo(ctx, input, output, opts, report); err != nil {
+		return fmt.Errorf("pass 2: %w", err)
+	}
+	return nil
+}
+
+// fallback is a simple fixed-quality encode for clips that can't be planned
+func fallback(ctx context.Context, t media.Transcoder, input, output string) error {
+	opts := media.VideoOptions{
+		Codec:  "libx264",
+		Preset: "veryfast",
+		CRF:    40,
+		Scale:  0.5,
+		FPS:    15,
+		Audio:  &media.AudioOptions{Codec: "aac", Bitrate: 32_000, SampleRate: 22050},
+	}
+	if err := t.EncodeVideo(ctx, input, output, opts, nil); err != nil {
+		os.Remove(output)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		logger.Error("Fallback compression error: %v", err)
+		return errors.New("fallback compression error")
+	}
+	return nil
+}
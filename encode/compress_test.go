@@ -0,0 +1,131 @@
+package encode
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"autoclipsend/media"
+	"autoclipsend/media/mediatest"
+)
+
+const testMaxSize = 10 * 1024 * 1024
+
+// newTestClip writes an oversized stand-in clip and returns its path and where to compress it to
+func newTestClip(t *testing.T) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	input := filepath.Join(dir, "clip.mp4")
+	if err := os.WriteFile(input, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(input, 3*testMaxSize); err != nil {
+		t.Fatal(err)
+	}
+	return input, filepath.Join(dir, "clip_compressed.mp4")
+}
+
+// secondPasses returns the options of every encode that wrote output
+func secondPasses(calls []mediatest.Call) []media.VideoOptions {
+	var opts []media.VideoOptions
+	for _, c := range calls {
+		if c.Method == "EncodeVideo" && c.Video.Pass == 2 {
+			opts = append(opts, c.Video)
+		}
+	}
+	return opts
+}
+
+func TestToSizeReplansAfterOvershoot(t *testing.T) {
+	input, output := newTestClip(t)
+	fake := &mediatest.Transcoder{
+		ProbeResult: mediatest.Clip(60, 1920, 1080, 60, 128_000),
+		VideoSize: func(opts media.VideoOptions, attempt int) int64 {
+			if attempt == 1 {
+				return testMaxSize + 1024*1024 // Rate control missed by a megabyte
+			}
+			return testMaxSize - 1024
+		},
+	}
+	var reports []Progress
+	strategy, err := ToSize(context.Background(), fake, input, output, testMaxSize, func(p Progress) { reports = append(reports, p) })
+	if err != nil {
+		t.Fatalf("ToSize() error = %v", err)
+	}
+	if info, err := os.Stat(output); err != nil || info.Size() > testMaxSize {
+		t.Fatalf("output %s: %v, size over the limit", output, err)
+	}
+	if !strings.Contains(strategy, "attempt 2") {
+		t.Errorf("strategy = %q, want it to mention attempt 2", strategy)
+	}
+
+	passes := secondPasses(fake.Calls())
+	if len(passes) != 2 {
+		t.Fatalf("got %d encodes, want 2", len(passes))
+	}
+	if passes[1].Bitrate >= passes[0].Bitrate {
+		t.Errorf("re-planned bitrate %d is not below the first %d", passes[1].Bitrate, passes[0].Bitrate)
+	}
+	for _, c := range fake.Calls() {
+		if c.Method == "EncodeVideo" && c.Video.PassLogFile == "" {
+			t.Errorf("two-pass encode without a pass log file: %+v", c.Video)
+		}
+	}
+
+	// Each attempt starts over at pass 0 with its own label
+	last := reports[len(reports)-1]
+	if last.Pass != 1 || last.Passes != 2 || last.Label != "Compressing again to fit the size limit" {
+		t.Errorf("last progress = %+v, want the second pass of the second attempt", last)
+	}
+}
+
+func TestToSizeGivesUp(t *testing.T) {
+	input, output := newTestClip(t)
+	fake := &mediatest.Transcoder{
+		ProbeResult: mediatest.Clip(60, 1920, 1080, 60, 128_000),
+		VideoSize:   func(media.VideoOptions, int) int64 { return testMaxSize + 1 },
+	}
+	_, err := ToSize(context.Background(), fake, input, output, testMaxSize, nil)
+	if err == nil {
+		t.Fatal("ToSize() succeeded, want an error")
+	}
+	if got := len(secondPasses(fake.Calls())); got != maxAttempts {
+		t.Errorf("got %d encodes, want %d", got, maxAttempts)
+	}
+	if _, err := os.Stat(output); !os.IsNotExist(err) {
+		t.Errorf("oversized output was left behind: %v", err)
+	}
+}
+
+func TestToSizeFallsBackWithoutDuration(t *testing.T) {
+	input, output := newTestClip(t)
+	fake := &mediatest.Transcoder{
+		ProbeErr:  errors.New("ffprobe failed"),
+		VideoSize: func(media.VideoOptions, int) int64 { return 1024 },
+	}
+	strategy, err := ToSize(context.Background(), fake, input, output, testMaxSize, nil)
+	if err != nil {
+		t.Fatalf("ToSize() error = %v", err)
+	}
+	if !strings.HasPrefix(strategy, "fallback") {
+		t.Errorf("strategy = %q, want the fallback", strategy)
+	}
+	calls := fake.Calls()
+	if last := calls[len(calls)-1]; last.Method != "EncodeVideo" || last.Video.CRF == 0 || last.Video.Pass != 0 {
+		t.Errorf("last call = %+v, want a single-pass CRF encode", last)
+	}
+}
+
+func TestToSizeCancelled(t *testing.T) {
+	input, output := newTestClip(t)
+	fake := &mediatest.Transcoder{ProbeResult: mediatest.Clip(60, 1920, 1080, 60, 128_000)}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := ToSize(ctx, fake, input, output, testMaxSize, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,129 @@
+// Package encode plans and runs re-encodes that fit a clip under a size limit.
+// It only drives a media.Transcoder, so it is tested with a fake one.
+package encode
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"autoclipsend/media"
+)
+
+// Target-size encoding tuning
+const (
+	SizeSafetyMargin = 0.05    // Headroom below MaxFileSize for container overhead and rate control error
+	minVideoBitrate  = 150_000 // bits per second, below this nothing is watchable
+	maxAudioBitrate  = 128_000
+	minAudioBitrate  = 32_000
+	minBitsPerPixel  = 0.07 // x264 gets visibly blocky below this
+	maxAttempts      = 3    // First plan plus corrective re-plans after an overshoot
+)
+
+// resolutionLadder lists the output heights tried, best first
+var resolutionLadder = []int{2160, 1440, 1080, 720, 540, 480, 360, 240}
+
+// Plan is the bitrate, resolution and frame rate for one encode
+type Plan struct {
+	VideoBitrate int64   // bits per second
+	AudioBitrate int64   // bits per second, 0 when the clip has no audio
+	Height       int     // Output height, 0 keeps the source resolution
+	FPS          float64 // Output frame rate, 0 keeps the source frame rate
+}
+
+func (p Plan) String() string {
+	res := "source resolution"
+	if p.Height > 0 {
+		res = fmt.Sprintf("%dp", p.Height)
+	}
+	fps := "source fps"
+	if p.FPS > 0 {
+		fps = fmt.Sprintf("%.0ffps", p.FPS)
+	}
+	return fmt.Sprintf("%s, %s, %d kbps video, %d kbps audio", res, fps, p.VideoBitrate/1000, p.AudioBitrate/1000)
+}
+
+// NewPlan computes the bitrates that make the clip land just under
+// targetBytes, then picks the largest resolution and frame rate that still
+// get enough bits per pixel to look decent.
+func NewPlan(info *media.Probe, targetBytes int64) (Plan, error) {
+	if info.Duration <= 0 {
+		return Plan{}, errors.New("unknown clip duration")
+	}
+
+	totalBitrate := int64(float64(targetBytes) * 8 * (1 - SizeSafetyMargin) / info.Duration)
+
+	var audioBitrate int64
+	if audio := info.Audio(); audio != nil {
+		audioBitrate = audio.BitRate
+		if audioBitrate <= 0 || audioBitrate > maxAudioBitrate {
+			audioBitrate = maxAudioBitrate
+		}
+		// On a tight budget audio shouldn't take more than a fifth of it
+		if audioBitrate > totalBitrate/5 {
+			audioBitrate = max(minAudioBitrate, totalBitrate/5)
+		}
+	}
+
+	plan := Plan{
+		VideoBitrate: totalBitrate - audioBitrate,
+		AudioBitrate: audioBitrate,
+	}
+	if plan.VideoBitrate < minVideoBitrate {
+		return Plan{}, fmt.Errorf("clip is too long to fit in %.1f MB (only %d kbps available)",
+			float64(targetBytes)/(1024*1024), totalBitrate/1000)
+	}
+
+	video := info.Video()
+	if video == nil || video.Width <= 0 || video.Height <= 0 {
+		return plan, nil // Can't reason about pixels, let the bitrate do the work
+	}
+
+	srcFPS := video.FPS
+	if srcFPS <= 0 {
+		srcFPS = 30
+	}
+	fpsOptions := []float64{math.Min(srcFPS, 60)}
+	if srcFPS > 30 {
+		fpsOptions = append(fpsOptions, 30)
+	}
+
+	heights := []int{video.Height}
+	for _, h := range resolutionLadder {
+		if h < video.Height {
+			heights = append(heights, h)
+		}
+	}
+
+	for _, h := range heights {
+		w := video.Width * h / video.Height
+		for _, fps := range fpsOptions {
+			bpp := float64(plan.VideoBitrate) / (float64(w*h) * fps)
+			if bpp >= minBitsPerPixel {
+				plan.setShape(video, h, fps)
+				return plan, nil
+			}
+		}
+	}
+
+	// Even the smallest rung is starved, use it at 30fps or less
+	plan.setShape(video, heights[len(heights)-1], math.Min(srcFPS, 30))
+	return plan, nil
+}
+
+// setShape records the output size and frame rate, leaving fields at 0 when they match the source
+func (p *Plan) setShape(video *media.Stream, height int, fps float64) {
+	if height != video.Height {
+		p.Height = height
+	}
+	if video.FPS <= 0 || fps < video.FPS-0.5 {
+		p.FPS = fps
+	}
+}
+
+// shrinkTarget scales targetBytes down by how far an encode aimed at it
+// overshot maxBytes, with the usual safety margin on top
+func shrinkTarget(targetBytes, actualBytes, maxBytes int64) int64 {
+	ratio := float64(maxBytes) / float64(actualBytes)
+	return int64(float64(targetBytes) * ratio * (1 - SizeSafetyMargin))
+}
@@ -0,0 +1,132 @@
+package encode
+
+import (
+	"testing"
+
+	"autoclipsend/media"
+	"autoclipsend/media/mediatest"
+)
+
+func TestNewPlan(t *testing.T) {
+	const mb = 1024 * 1024
+	tests := []struct {
+		name        string
+		probe       *media.Probe
+		target      int64
+		wantErr     bool
+		wantHeight  int
+		wantFPS     float64
+		wantAudio   int64
+		wantNoAudio bool
+	}{
+		{
+			name:       "long 1080p60 clip drops resolution and frame rate",
+			probe:      mediatest.Clip(60, 1920, 1080, 60, 160_000),
+			target:     10 * mb,
+			wantHeight: 540,
+			wantFPS:    30,
+			wantAudio:  maxAudioBitrate,
+		},
+		{
+			name:       "short 720p30 clip keeps the source shape",
+			probe:      mediatest.Clip(10, 1280, 720, 30, 96_000),
+			target:     10 * mb,
+			wantHeight: 0,
+			wantFPS:    0,
+			wantAudio:  96_000,
+		},
+		{
+			name:       "tight budget limits audio to a fifth",
+			probe:      mediatest.Clip(30, 1280, 720, 30, 128_000),
+			target:     1 * mb,
+			wantHeight: 240,
+			wantFPS:    0,
+			wantAudio:  53_127, // A fifth of 1 MB over 30 s less the margin
+		},
+		{
+			name:        "no audio stream",
+			probe:       mediatest.Clip(20, 1920, 1080, 30, -1),
+			target:      25 * mb,
+			wantHeight:  0,
+			wantNoAudio: true,
+		},
+		{
+			name:       "audio only file keeps the bitrate decision",
+			probe:      mediatest.Clip(20, 0, 0, 0, 64_000),
+			target:     5 * mb,
+			wantAudio:  64_000,
+			wantHeight: 0,
+		},
+		{
+			name:    "clip too long for the size",
+			probe:   mediatest.Clip(600, 1920, 1080, 60, 128_000),
+			target:  1 * mb,
+			wantErr: true,
+		},
+		{
+			name:    "unknown duration",
+			probe:   mediatest.Clip(0, 1920, 1080, 60, 128_000),
+			target:  10 * mb,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := NewPlan(tt.probe, tt.target)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NewPlan() = %s, want an error", plan)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewPlan() error = %v", err)
+			}
+
+			budget := float64(tt.target) * 8 / tt.probe.Duration
+			if total := float64(plan.VideoBitrate + plan.AudioBitrate); total > budget*(1-SizeSafetyMargin)+1 {
+				t.Errorf("plan uses %.0f bps, budget with margin is %.0f", total, budget*(1-SizeSafetyMargin))
+			}
+			if plan.VideoBitrate < minVideoBitrate {
+				t.Errorf("VideoBitrate = %d, below the minimum", plan.VideoBitrate)
+			}
+			if plan.Height != tt.wantHeight {
+				t.Errorf("Height = %d, want %d", plan.Height, tt.wantHeight)
+			}
+			if plan.FPS != tt.wantFPS {
+				t.Errorf("FPS = %g, want %g", plan.FPS, tt.wantFPS)
+			}
+			if tt.wantNoAudio {
+				if plan.AudioBitrate != 0 {
+					t.Errorf("AudioBitrate = %d, want 0", plan.AudioBitrate)
+				}
+			} else if plan.AudioBitrate != tt.wantAudio {
+				t.Errorf("AudioBitrate = %d, want %d", plan.AudioBitrate, tt.wantAudio)
+			}
+		})
+	}
+}
+
+func TestShrinkTarget(t *testing.T) {
+	tests := []struct {
+		name                      string
+		target, actual, max, want int64
+	}{
+		{"overshot by a fifth", 10_000_000, 12_000_000, 10_000_000, 7_916_666},
+		{"barely over", 10_000_000, 10_100_000, 10_000_000, 9_405_940},
+		{"earlier target already reduced", 8_000_000, 11_000_000, 10_000_000, 6_909_090},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := shrinkTarget(tt.target, tt.actual, tt.max)
+			if got != tt.want {
+				t.Errorf("shrinkTarget(%d, %d, %d) = %d, want %d", tt.target, tt.actual, tt.max, got, tt.want)
+			}
+			if got >= tt.target {
+				t.Errorf("shrinkTarget did not shrink: %d >= %d", got, tt.target)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"autoclipsend/encode"
+)
+
+// encodeProgress turns transcoder progress reports from one or more passes
+// over the same clip into smooth compressionProgress events with ETA and
+// speed, and moves the job's sendProgress through its processing stage
+type encodeProgress struct {
+	app      *App
+	jobID    string  // Job the events are tagged with
+	stage    string  // sendProgress stage, e.g. "compressing"
+	duration float64 // Clip length in seconds, from ffprobe
+	passes   int     // Number of ffmpeg runs over the full clip
+	pass     int     // Zero-based index of the run in progress
+	label    string  // Shown before the percentage, e.g. "Compressing to 720p"
+}
+
+func (a *App) newEncodeProgress(jobID, stage string, duration float64, passes int, label string) *encodeProgress {
+	return &encodeProgress{app: a, jobID: jobID, stage: stage, duration: duration, passes: passes, label: label}
+}
+
+// update reports progress from encode.ToSize, whose attempts and passes move
+// the label and pass along
+func (p *encodeProgress) update(progress encode.Progress) {
+	p.label, p.duration = progress.Label, progress.Duration
+	p.pass, p.passes = progress.Pass, progress.Passes
+	p.report(progress.OutTime, progress.Speed)
+}
+
+// fraction is how much of the encode is done after outTime of the current pass
+func (p *encodeProgress) fraction(outTime time.Duration) float64 {
+	done := min(outTime.Seconds(), p.duration)
+	return (float64(p.pass) + done/p.duration) / float64(p.passes)
+}
+
+// report emits progress for the current pass having encoded outTime of the
+// clip at speed. It is used as a media.ProgressFunc.
+func (p *encodeProgress) report(outTime time.Duration, speed float64) {
+	if p == nil || p.duration <= 0 {
+		return
+	}
+
+	fraction := p.fraction(outTime)
+	event := map[string]interface{}{
+		"stage":    p.stage,
+		"progress": fraction,
+	}
+	if speed > 0 {
+		// Remaining media across this and the later passes, at the current speed
+		remaining := float64(p.passes-p.pass)*p.duration - min(outTime.Seconds(), p.duration)
+		eta := remaining / speed
+		event["eta"] = eta
+		event["speed"] = speed
+		event["message"] = fmt.Sprintf("%.0f%% – %s remaining at %.1fx", fraction*100, formatClipDuration(eta), speed)
+	} else {
+		event["message"] = fmt.Sprintf("%.0f%%", fraction*100)
+	}
+	p.app.emitCompressionProgress(p.jobID, event)
+
+	p.app.emitSendProgress(p.jobID, map[string]interface{}{
+		"stage":    p.stage,
+		"progress": processProgressStart + fraction*(uploadProgressStart-processProgressStart),
+		"message":  p.label,
+	})
+}
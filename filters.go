@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"autoclipsend/clipfilter"
+	"autoclipsend/logger"
+)
+
+// clipFilterFor returns the filter of the watch entry containing filePath, and its folder
+func (a *App) clipFilterFor(filePath string) (clipfilter.Filter, string) {
+	entry, root, ok := a.watchEntryFor(filePath)
+	if !ok {
+		return clipfilter.Filter{}, ""
+	}
+	return entry.Filter, root
+}
+
+// isClipCandidate checks a file's name against the filter of its folder
+func (a *App) isClipCandidate(filePath string) bool {
+	filter, root := a.clipFilterFor(filePath)
+	var rel string
+	if root != "" {
+		rel, _ = relativeTo(root, filePath)
+	}
+	return filter.Matches(filePath, rel)
+}
+
+// checkClipLimits applies the size and duration limits of a file's folder.
+// Duration needs ffprobe, so this runs once the file is complete.
+func (a *App) checkClipLimits(ctx context.Context, filePath string) error {
+	filter, _ := a.clipFilterFor(filePath)
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+	sizeMB := float64(info.Size()) / (1024 * 1024)
+	if filter.MinSizeMB > 0 && sizeMB < filter.MinSizeMB {
+		return fmt.Errorf("%.1f MB is below the %.1f MB minimum", sizeMB, filter.MinSizeMB)
+	}
+	if filter.MaxSizeMB > 0 && sizeMB > filter.MaxSizeMB {
+		return fmt.Errorf("%.1f MB is above the %.1f MB maximum", sizeMB, filter.MaxSizeMB)
+	}
+
+	if filter.MinDuration <= 0 && filter.MaxDuration <= 0 {
+		return nil
+	}
+	probe, err := a.transcoder.Probe(ctx, filePath)
+	if err != nil {
+		// Better to offer a clip we can't measure than to silently drop it
+		logger.Warn("Could not read duration of %s, skipping duration limits: %v", filePath, err)
+		return nil
+	}
+	if filter.MinDuration > 0 && probe.Duration < filter.MinDuration {
+		return fmt.Errorf("%s is shorter than the %s minimum", formatClipDuration(probe.Duration), formatClipDuration(filter.MinDuration))
+	}
+	if filter.MaxDuration > 0 && probe.Duration > filter.MaxDuration {
+		return fmt.Errorf("%s is longer than the %s maximum", formatClipDuration(probe.Duration), formatClipDuration(filter.MaxDuration))
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"autoclipsend/clipfilter"
+)
+
+// The matching itself is tested in clipfilter, this checks which filter applies
+func TestIsClipCandidate(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "Clips")
+	a := &App{
+		configManager: &ConfigManager{config: &Config{}},
+		watchers: map[string]*runningWatch{
+			"clips": {entry: WatchEntry{ID: "clips", Enabled: true, Filter: clipfilter.Filter{Include: []string{"Valorant/*"}}}, path: root},
+		},
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{"include folder with capitals", filepath.Join(root, "Valorant", "Clip 1.mp4"), true},
+		{"include folder other game", filepath.Join(root, "Apex", "Clip 1.mp4"), false},
+		{"outside the watched folder ignores its filter", filepath.Join(filepath.Dir(root), "Other", "clip.mp4"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := a.isClipCandidate(tt.path); got != tt.want {
+				t.Errorf("isClipCandidate(%s) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
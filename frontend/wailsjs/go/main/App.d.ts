@@ -3,42 +3,80 @@
 import {version} from '../models';
 import {main} from '../models';
 
+export function AddWatchEntry(arg1:main.WatchEntry):Promise<main.WatchEntry>;
+
 export function BringToFront():Promise<void>;
 
+export function CancelAutoSend(arg1:string):Promise<void>;
+
+export function CancelSend(arg1:string):Promise<void>;
+
 export function CheckForUpdates():Promise<version.UpdateInfo>;
 
 export function CreateDesktopShortcut():Promise<void>;
 
+export function DeleteSentMessage(arg1:string,arg2:string):Promise<void>;
+
+export function DismissMissedClips():Promise<void>;
+
+export function EditSentCaption(arg1:string,arg2:string,arg3:string):Promise<void>;
+
 export function ExportData(arg1:string):Promise<void>;
 
 export function GetAppStatus():Promise<main.AppStatus>;
 
+export function GetAutoSendCountdowns():Promise<Array<main.AutoSendCountdown>>;
+
+export function GetAutoSendRules():Promise<Array<main.AutoSendRule>>;
+
 export function GetBuildInfo():Promise<version.BuildInfo>;
 
+export function GetClipDestinations(arg1:string):Promise<Array<main.Destination>>;
+
+export function GetClipSends(arg1:string):Promise<Array<main.SentEntry>>;
+
 export function GetConfig():Promise<main.Config>;
 
 export function GetDataPath():Promise<string>;
 
+export function GetDestinations():Promise<Array<main.Destination>>;
+
 export function GetFileSize(arg1:string):Promise<number>;
 
+export function GetHistoryGames():Promise<Array<string>>;
+
 export function GetMedalTVClipFolder():Promise<string>;
 
 export function GetMedalTVClips():Promise<Array<main.ClipDisplayData>>;
 
+export function GetMissedClips():Promise<Array<main.MissedClip>>;
+
 export function GetMonitoredPaths():Promise<Array<string>>;
 
+export function GetMonitoringState():Promise<main.MonitorStatus>;
+
 export function GetNVIDIACurrentDirectory():Promise<string>;
 
+export function GetPendingClips():Promise<Array<main.PendingClip>>;
+
+export function GetRoutes():Promise<Array<main.RouteRule>>;
+
+export function GetSendQueue():Promise<Array<main.SendJob>>;
+
 export function GetStatistics():Promise<main.Stats>;
 
 export function GetStorageInfo():Promise<Record<string, any>>;
 
+export function GetUndoableSend():Promise<main.SendRecord>;
+
 export function GetUptime():Promise<string>;
 
 export function GetVersion():Promise<string>;
 
 export function GetVersionInfo():Promise<Record<string, string>>;
 
+export function GetWatchEntries():Promise<Array<main.WatchEntry>>;
+
 export function HandleWindowClose():Promise<void>;
 
 export function HasDesktopShortcut():Promise<boolean>;
@@ -47,6 +85,8 @@ export function ImportData(arg1:string):Promise<void>;
 
 export function InitTray():Promise<void>;
 
+export function IsClipSent(arg1:string):Promise<boolean>;
+
 export function IsInWindowsStartup():Promise<boolean>;
 
 export function IsVisible():Promise<boolean>;
@@ -59,22 +99,50 @@ export function MinimizeToTray():Promise<void>;
 
 export function OpenUpdateURL(arg1:string):Promise<void>;
 
+export function PauseMonitoring():Promise<void>;
+
+export function PreviewTemplate(arg1:string,arg2:string):Promise<string>;
+
+export function QueryHistory(arg1:main.HistoryQuery):Promise<main.HistoryPage>;
+
+export function QueueSend(arg1:string,arg2:string,arg3:boolean):Promise<main.SendJob>;
+
 export function RemoveDesktopShortcut():Promise<void>;
 
+export function RemoveWatchEntry(arg1:string):Promise<void>;
+
 export function ResetSessionStats():Promise<void>;
 
 export function RestartMonitoring():Promise<void>;
 
-export function SaveConfig(arg1:main.Config):Promise<void>;
+export function ResumeMonitoring():Promise<void>;
+
+export function RetrySend(arg1:string):Promise<void>;
+
+export function SaveConfig(arg1:Record<string, any>):Promise<void>;
 
 export function SelectFolder():Promise<string>;
 
-export function SendClipToDiscord(arg1:string):Promise<void>;
+export function SendAutoSendNow(arg1:string):Promise<void>;
+
+export function SendClipToDiscord(arg1:string):Promise<main.SendJob>;
+
+export function SendMissedClips(arg1:Array<string>,arg2:boolean):Promise<Array<main.SendJob>>;
+
+export function SendPendingClip(arg1:string,arg2:string,arg3:boolean):Promise<main.SendJob>;
 
 export function SendToDiscord(arg1:string,arg2:string,arg3:boolean):Promise<void>;
 
+export function SetAutoSendRules(arg1:Array<main.AutoSendRule>):Promise<void>;
+
 export function SetDesktopShortcut(arg1:boolean):Promise<void>;
 
+export function SetDestinations(arg1:Array<main.Destination>):Promise<void>;
+
+export function SetRoutes(arg1:Array<main.RouteRule>):Promise<void>;
+
+export function SetSendPriority(arg1:string,arg2:number):Promise<void>;
+
 export function SetWebhookURL(arg1:string):Promise<void>;
 
 export function SetWindowsStartup(arg1:boolean):Promise<void>;
@@ -85,10 +153,20 @@ export function ShowFromTray():Promise<void>;
 
 export function ShowNotification(arg1:string,arg2:string):Promise<void>;
 
+export function SkipMissedClips(arg1:Array<string>):Promise<void>;
+
+export function SkipPendingClip(arg1:string):Promise<void>;
+
+export function SnoozePendingClip(arg1:string,arg2:number):Promise<void>;
+
 export function StartMonitoring():Promise<void>;
 
 export function StopMonitoring():Promise<void>;
 
 export function ToggleVisibility():Promise<void>;
 
+export function UndoLastSend():Promise<void>;
+
 export function UpdateMonitorPath(arg1:string):Promise<void>;
+
+export function UpdateWatchEntry(arg1:main.WatchEntry):Promise<void>;
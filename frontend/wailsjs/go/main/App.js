@@ -2,10 +2,22 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AddWatchEntry(arg1) {
+  return window['go']['main']['App']['AddWatchEntry'](arg1);
+}
+
 export function BringToFront() {
   return window['go']['main']['App']['BringToFront']();
 }
 
+export function CancelAutoSend(arg1) {
+  return window['go']['main']['App']['CancelAutoSend'](arg1);
+}
+
+export function CancelSend(arg1) {
+  return window['go']['main']['App']['CancelSend'](arg1);
+}
+
 export function CheckForUpdates() {
   return window['go']['main']['App']['CheckForUpdates']();
 }
@@ -14,6 +26,18 @@ export function CreateDesktopShortcut() {
   return window['go']['main']['App']['CreateDesktopShortcut']();
 }
 
+export function DeleteSentMessage(arg1, arg2) {
+  return window['go']['main']['App']['DeleteSentMessage'](arg1, arg2);
+}
+
+export function DismissMissedClips() {
+  return window['go']['main']['App']['DismissMissedClips']();
+}
+
+export function EditSentCaption(arg1, arg2, arg3) {
+  return window['go']['main']['App']['EditSentCaption'](arg1, arg2, arg3);
+}
+
 export function ExportData(arg1) {
   return window['go']['main']['App']['ExportData'](arg1);
 }
@@ -22,10 +46,26 @@ export function GetAppStatus() {
   return window['go']['main']['App']['GetAppStatus']();
 }
 
+export function GetAutoSendCountdowns() {
+  return window['go']['main']['App']['GetAutoSendCountdowns']();
+}
+
+export function GetAutoSendRules() {
+  return window['go']['main']['App']['GetAutoSendRules']();
+}
+
 export function GetBuildInfo() {
   return window['go']['main']['App']['GetBuildInfo']();
 }
 
+export function GetClipDestinations(arg1) {
+  return window['go']['main']['App']['GetClipDestinations'](arg1);
+}
+
+export function GetClipSends(arg1) {
+  return window['go']['main']['App']['GetClipSends'](arg1);
+}
+
 export function GetConfig() {
   return window['go']['main']['App']['GetConfig']();
 }
@@ -34,10 +74,18 @@ export function GetDataPath() {
   return window['go']['main']['App']['GetDataPath']();
 }
 
+export function GetDestinations() {
+  return window['go']['main']['App']['GetDestinations']();
+}
+
 export function GetFileSize(arg1) {
   return window['go']['main']['App']['GetFileSize'](arg1);
 }
 
+export function GetHistoryGames() {
+  return window['go']['main']['App']['GetHistoryGames']();
+}
+
 export function GetMedalTVClipFolder() {
   return window['go']['main']['App']['GetMedalTVClipFolder']();
 }
@@ -46,14 +94,34 @@ export function GetMedalTVClips() {
   return window['go']['main']['App']['GetMedalTVClips']();
 }
 
+export function GetMissedClips() {
+  return window['go']['main']['App']['GetMissedClips']();
+}
+
 export function GetMonitoredPaths() {
   return window['go']['main']['App']['GetMonitoredPaths']();
 }
 
+export function GetMonitoringState() {
+  return window['go']['main']['App']['GetMonitoringState']();
+}
+
 export function GetNVIDIACurrentDirectory() {
   return window['go']['main']['App']['GetNVIDIACurrentDirectory']();
 }
 
+export function GetPendingClips() {
+  return window['go']['main']['App']['GetPendingClips']();
+}
+
+export function GetRoutes() {
+  return window['go']['main']['App']['GetRoutes']();
+}
+
+export function GetSendQueue() {
+  return window['go']['main']['App']['GetSendQueue']();
+}
+
 export function GetStatistics() {
   return window['go']['main']['App']['GetStatistics']();
 }
@@ -62,6 +130,10 @@ export function GetStorageInfo() {
   return window['go']['main']['App']['GetStorageInfo']();
 }
 
+export function GetUndoableSend() {
+  return window['go']['main']['App']['GetUndoableSend']();
+}
+
 export function GetUptime() {
   return window['go']['main']['App']['GetUptime']();
 }
@@ -74,6 +146,10 @@ export function GetVersionInfo() {
   return window['go']['main']['App']['GetVersionInfo']();
 }
 
+export function GetWatchEntries() {
+  return window['go']['main']['App']['GetWatchEntries']();
+}
+
 export function HandleWindowClose() {
   return window['go']['main']['App']['HandleWindowClose']();
 }
@@ -90,6 +166,10 @@ export function InitTray() {
   return window['go']['main']['App']['InitTray']();
 }
 
+export function IsClipSent(arg1) {
+  return window['go']['main']['App']['IsClipSent'](arg1);
+}
+
 export function IsInWindowsStartup() {
   return window['go']['main']['App']['IsInWindowsStartup']();
 }
@@ -114,10 +194,30 @@ export function OpenUpdateURL(arg1) {
   return window['go']['main']['App']['OpenUpdateURL'](arg1);
 }
 
+export function PauseMonitoring() {
+  return window['go']['main']['App']['PauseMonitoring']();
+}
+
+export function PreviewTemplate(arg1, arg2) {
+  return window['go']['main']['App']['PreviewTemplate'](arg1, arg2);
+}
+
+export function QueryHistory(arg1) {
+  return window['go']['main']['App']['QueryHistory'](arg1);
+}
+
+export function QueueSend(arg1, arg2, arg3) {
+  return window['go']['main']['App']['QueueSend'](arg1, arg2, arg3);
+}
+
 export function RemoveDesktopShortcut() {
   return window['go']['main']['App']['RemoveDesktopShortcut']();
 }
 
+export function RemoveWatchEntry(arg1) {
+  return window['go']['main']['App']['RemoveWatchEntry'](arg1);
+}
+
 export function ResetSessionStats() {
   return window['go']['main']['App']['ResetSessionStats']();
 }
@@ -126,6 +226,14 @@ export function RestartMonitoring() {
   return window['go']['main']['App']['RestartMonitoring']();
 }
 
+export function ResumeMonitoring() {
+  return window['go']['main']['App']['ResumeMonitoring']();
+}
+
+export function RetrySend(arg1) {
+  return window['go']['main']['App']['RetrySend'](arg1);
+}
+
 export function SaveConfig(arg1) {
   return window['go']['main']['App']['SaveConfig'](arg1);
 }
@@ -134,18 +242,46 @@ export function SelectFolder() {
   return window['go']['main']['App']['SelectFolder']();
 }
 
+export function SendAutoSendNow(arg1) {
+  return window['go']['main']['App']['SendAutoSendNow'](arg1);
+}
+
 export function SendClipToDiscord(arg1) {
   return window['go']['main']['App']['SendClipToDiscord'](arg1);
 }
 
+export function SendMissedClips(arg1, arg2) {
+  return window['go']['main']['App']['SendMissedClips'](arg1, arg2);
+}
+
+export function SendPendingClip(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SendPendingClip'](arg1, arg2, arg3);
+}
+
 export function SendToDiscord(arg1, arg2, arg3) {
   return window['go']['main']['App']['SendToDiscord'](arg1, arg2, arg3);
 }
 
+export function SetAutoSendRules(arg1) {
+  return window['go']['main']['App']['SetAutoSendRules'](arg1);
+}
+
 export function SetDesktopShortcut(arg1) {
   return window['go']['main']['App']['SetDesktopShortcut'](arg1);
 }
 
+export function SetDestinations(arg1) {
+  return window['go']['main']['App']['SetDestinations'](arg1);
+}
+
+export function SetRoutes(arg1) {
+  return window['go']['main']['App']['SetRoutes'](arg1);
+}
+
+export function SetSendPriority(arg1, arg2) {
+  return window['go']['main']['App']['SetSendPriority'](arg1, arg2);
+}
+
 export function SetWebhookURL(arg1) {
   return window['go']['main']['App']['SetWebhookURL'](arg1);
 }
@@ -166,6 +302,18 @@ export function ShowNotification(arg1, arg2) {
   return window['go']['main']['App']['ShowNotification'](arg1, arg2);
 }
 
+export function SkipMissedClips(arg1) {
+  return window['go']['main']['App']['SkipMissedClips'](arg1);
+}
+
+export function SkipPendingClip(arg1) {
+  return window['go']['main']['App']['SkipPendingClip'](arg1);
+}
+
+export function SnoozePendingClip(arg1, arg2) {
+  return window['go']['main']['App']['SnoozePendingClip'](arg1, arg2);
+}
+
 export function StartMonitoring() {
   return window['go']['main']['App']['StartMonitoring']();
 }
@@ -178,6 +326,14 @@ export function ToggleVisibility() {
   return window['go']['main']['App']['ToggleVisibility']();
 }
 
+export function UndoLastSend() {
+  return window['go']['main']['App']['UndoLastSend']();
+}
+
 export function UpdateMonitorPath(arg1) {
   return window['go']['main']['App']['UpdateMonitorPath'](arg1);
 }
+
+export function UpdateWatchEntry(arg1) {
+  return window['go']['main']['App']['UpdateWatchEntry'](arg1);
+}
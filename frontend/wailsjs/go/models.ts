@@ -1,8 +1,38 @@
+export namespace clipfilter {
+	
+	export class Filter {
+	    include: string[];
+	    exclude: string[];
+	    extra_extensions: string[];
+	    min_size_mb: number;
+	    max_size_mb: number;
+	    min_duration: number;
+	    max_duration: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Filter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.include = source["include"];
+	        this.exclude = source["exclude"];
+	        this.extra_extensions = source["extra_extensions"];
+	        this.min_size_mb = source["min_size_mb"];
+	        this.max_size_mb = source["max_size_mb"];
+	        this.min_duration = source["min_duration"];
+	        this.max_duration = source["max_duration"];
+	    }
+	}
+
+}
+
 export namespace main {
 	
 	export class AppStatus {
 	    uptime: string;
 	    isMonitoring: boolean;
+	    monitorState: string;
 	    monitorPath: string;
 	    videosSent: number;
 	    audiosSent: number;
@@ -21,6 +51,7 @@ export namespace main {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.uptime = source["uptime"];
 	        this.isMonitoring = source["isMonitoring"];
+	        this.monitorState = source["monitorState"];
 	        this.monitorPath = source["monitorPath"];
 	        this.videosSent = source["videosSent"];
 	        this.audiosSent = source["audiosSent"];
@@ -32,6 +63,71 @@ export namespace main {
 	        this.nvidiaPath = source["nvidiaPath"];
 	    }
 	}
+	export class AutoSendCountdown {
+	    filePath: string;
+	    fileName: string;
+	    action: string;
+	    reason: string;
+	    // Go type: time
+	    sendAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new AutoSendCountdown(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.fileName = source["fileName"];
+	        this.action = source["action"];
+	        this.reason = source["reason"];
+	        this.sendAt = this.convertValues(source["sendAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AutoSendRule {
+	    name: string;
+	    enabled: boolean;
+	    source: string;
+	    path: string;
+	    subfolder: string;
+	    game_title: string;
+	    action: string;
+	    countdown: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AutoSendRule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.enabled = source["enabled"];
+	        this.source = source["source"];
+	        this.path = source["path"];
+	        this.subfolder = source["subfolder"];
+	        this.game_title = source["game_title"];
+	        this.action = source["action"];
+	        this.countdown = source["countdown"];
+	    }
+	}
 	export class ClipDisplayData {
 	    uuid: string;
 	    title: string;
@@ -42,6 +138,7 @@ export namespace main {
 	    thumbnailUrl: string;
 	    filePath: string;
 	    status: string;
+	    sent: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new ClipDisplayData(source);
@@ -58,6 +155,7 @@ export namespace main {
 	        this.thumbnailUrl = source["thumbnailUrl"];
 	        this.filePath = source["filePath"];
 	        this.status = source["status"];
+	        this.sent = source["sent"];
 	    }
 	}
 	export class Config {
@@ -73,6 +171,22 @@ export namespace main {
 	    use_medaltv_path: boolean;
 	    use_nvidia_path: boolean;
 	    use_custom_path: boolean;
+	    send_workers: number;
+	    upload_timeout: number;
+	    duplicate_policy: string;
+	    undo_window: number;
+	    oversize_strategy: string;
+	    watches: WatchEntry[];
+	    poll_interval: number;
+	    destinations: Destination[];
+	    routes: RouteRule[];
+	    auto_send_rules: AutoSendRule[];
+	    embed_enabled: boolean;
+	    embed_color: string;
+	    webhook_username: string;
+	    webhook_avatar_url: string;
+	    caption_template: string;
+	    filename_template: string;
 	    total_clips: number;
 	    // Go type: time
 	    last_clip_time: any;
@@ -82,6 +196,8 @@ export namespace main {
 	    start_time: any;
 	    // Go type: time
 	    last_update_time: any;
+	    // Go type: time
+	    last_processed_time: any;
 	
 	    static createFrom(source: any = {}) {
 	        return new Config(source);
@@ -101,12 +217,227 @@ export namespace main {
 	        this.use_medaltv_path = source["use_medaltv_path"];
 	        this.use_nvidia_path = source["use_nvidia_path"];
 	        this.use_custom_path = source["use_custom_path"];
+	        this.send_workers = source["send_workers"];
+	        this.upload_timeout = source["upload_timeout"];
+	        this.duplicate_policy = source["duplicate_policy"];
+	        this.undo_window = source["undo_window"];
+	        this.oversize_strategy = source["oversize_strategy"];
+	        this.watches = this.convertValues(source["watches"], WatchEntry);
+	        this.poll_interval = source["poll_interval"];
+	        this.destinations = this.convertValues(source["destinations"], Destination);
+	        this.routes = this.convertValues(source["routes"], RouteRule);
+	        this.auto_send_rules = this.convertValues(source["auto_send_rules"], AutoSendRule);
+	        this.embed_enabled = source["embed_enabled"];
+	        this.embed_color = source["embed_color"];
+	        this.webhook_username = source["webhook_username"];
+	        this.webhook_avatar_url = source["webhook_avatar_url"];
+	        this.caption_template = source["caption_template"];
+	        this.filename_template = source["filename_template"];
 	        this.total_clips = source["total_clips"];
 	        this.last_clip_time = this.convertValues(source["last_clip_time"], null);
 	        this.session_clips = source["session_clips"];
 	        this.total_size_bytes = source["total_size_bytes"];
 	        this.start_time = this.convertValues(source["start_time"], null);
 	        this.last_update_time = this.convertValues(source["last_update_time"], null);
+	        this.last_processed_time = this.convertValues(source["last_processed_time"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Destination {
+	    id: string;
+	    name: string;
+	    webhook_url: string;
+	    enabled: boolean;
+	    default: boolean;
+	    username: string;
+	    avatar_url: string;
+	    caption_template: string;
+	    filename_template: string;
+	    thread_id: string;
+	    forum_post: boolean;
+	    thread_name_template: string;
+	    forum_tags: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Destination(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.webhook_url = source["webhook_url"];
+	        this.enabled = source["enabled"];
+	        this.default = source["default"];
+	        this.username = source["username"];
+	        this.avatar_url = source["avatar_url"];
+	        this.caption_template = source["caption_template"];
+	        this.filename_template = source["filename_template"];
+	        this.thread_id = source["thread_id"];
+	        this.forum_post = source["forum_post"];
+	        this.thread_name_template = source["thread_name_template"];
+	        this.forum_tags = source["forum_tags"];
+	    }
+	}
+	export class DestinationResult {
+	    destinationId: string;
+	    name: string;
+	    success: boolean;
+	    error?: string;
+	    // Go type: time
+	    sentAt: any;
+	    messageId?: string;
+	    channelId?: string;
+	    threadId?: string;
+	    partIds?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new DestinationResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.destinationId = source["destinationId"];
+	        this.name = source["name"];
+	        this.success = source["success"];
+	        this.error = source["error"];
+	        this.sentAt = this.convertValues(source["sentAt"], null);
+	        this.messageId = source["messageId"];
+	        this.channelId = source["channelId"];
+	        this.threadId = source["threadId"];
+	        this.partIds = source["partIds"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HistoryPage {
+	    records: SendRecord[];
+	    total: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HistoryPage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.records = this.convertValues(source["records"], SendRecord);
+	        this.total = source["total"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class HistoryQuery {
+	    // Go type: time
+	    from: any;
+	    // Go type: time
+	    to: any;
+	    game: string;
+	    outcome: string;
+	    search: string;
+	    offset: number;
+	    limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new HistoryQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.from = this.convertValues(source["from"], null);
+	        this.to = this.convertValues(source["to"], null);
+	        this.game = source["game"];
+	        this.outcome = source["outcome"];
+	        this.search = source["search"];
+	        this.offset = source["offset"];
+	        this.limit = source["limit"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MissedClip {
+	    filePath: string;
+	    fileName: string;
+	    size: number;
+	    // Go type: time
+	    recordedAt: any;
+	    sent: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new MissedClip(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.fileName = source["fileName"];
+	        this.size = source["size"];
+	        this.recordedAt = this.convertValues(source["recordedAt"], null);
+	        this.sent = source["sent"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -127,6 +458,262 @@ export namespace main {
 		    return a;
 		}
 	}
+	export class MonitorStatus {
+	    state: string;
+	    error?: string;
+	    paths: string[];
+	    buffered: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MonitorStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.state = source["state"];
+	        this.error = source["error"];
+	        this.paths = source["paths"];
+	        this.buffered = source["buffered"];
+	    }
+	}
+	export class PendingClip {
+	    filePath: string;
+	    fileName: string;
+	    // Go type: time
+	    detectedAt: any;
+	    // Go type: time
+	    snoozedUntil?: any;
+	    transient?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PendingClip(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.filePath = source["filePath"];
+	        this.fileName = source["fileName"];
+	        this.detectedAt = this.convertValues(source["detectedAt"], null);
+	        this.snoozedUntil = this.convertValues(source["snoozedUntil"], null);
+	        this.transient = source["transient"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RouteRule {
+	    name: string;
+	    source: string;
+	    subfolder: string;
+	    game_title: string;
+	    destinations: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RouteRule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.source = source["source"];
+	        this.subfolder = source["subfolder"];
+	        this.game_title = source["game_title"];
+	        this.destinations = source["destinations"];
+	    }
+	}
+	export class SendJob {
+	    id: string;
+	    filePath: string;
+	    customName: string;
+	    audioOnly: boolean;
+	    priority: number;
+	    state: string;
+	    attempts: number;
+	    error?: string;
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    updatedAt: any;
+	    results?: DestinationResult[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SendJob(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.filePath = source["filePath"];
+	        this.customName = source["customName"];
+	        this.audioOnly = source["audioOnly"];
+	        this.priority = source["priority"];
+	        this.state = source["state"];
+	        this.attempts = source["attempts"];
+	        this.error = source["error"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	        this.results = this.convertValues(source["results"], DestinationResult);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SendRecord {
+	    jobId: string;
+	    attempt: number;
+	    filePath: string;
+	    fileName: string;
+	    source: string;
+	    gameTitle: string;
+	    audioOnly: boolean;
+	    destinations: DestinationResult[];
+	    originalSize: number;
+	    finalSize: number;
+	    strategy: string;
+	    stages: StageTiming[];
+	    outcome: string;
+	    error?: string;
+	    // Go type: time
+	    startedAt: any;
+	    // Go type: time
+	    finishedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new SendRecord(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.jobId = source["jobId"];
+	        this.attempt = source["attempt"];
+	        this.filePath = source["filePath"];
+	        this.fileName = source["fileName"];
+	        this.source = source["source"];
+	        this.gameTitle = source["gameTitle"];
+	        this.audioOnly = source["audioOnly"];
+	        this.destinations = this.convertValues(source["destinations"], DestinationResult);
+	        this.originalSize = source["originalSize"];
+	        this.finalSize = source["finalSize"];
+	        this.strategy = source["strategy"];
+	        this.stages = this.convertValues(source["stages"], StageTiming);
+	        this.outcome = source["outcome"];
+	        this.error = source["error"];
+	        this.startedAt = this.convertValues(source["startedAt"], null);
+	        this.finishedAt = this.convertValues(source["finishedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SentEntry {
+	    hash: string;
+	    filePath: string;
+	    size: number;
+	    // Go type: time
+	    modTime: any;
+	    destinationId: string;
+	    destinationName: string;
+	    messageId?: string;
+	    threadId?: string;
+	    // Go type: time
+	    sentAt: any;
+	    variant: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SentEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hash = source["hash"];
+	        this.filePath = source["filePath"];
+	        this.size = source["size"];
+	        this.modTime = this.convertValues(source["modTime"], null);
+	        this.destinationId = source["destinationId"];
+	        this.destinationName = source["destinationName"];
+	        this.messageId = source["messageId"];
+	        this.threadId = source["threadId"];
+	        this.sentAt = this.convertValues(source["sentAt"], null);
+	        this.variant = source["variant"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StageTiming {
+	    name: string;
+	    seconds: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StageTiming(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.seconds = source["seconds"];
+	    }
+	}
 	export class Stats {
 	    total_clips: number;
 	    // Go type: time
@@ -137,6 +724,8 @@ export namespace main {
 	    start_time: any;
 	    // Go type: time
 	    last_update_time: any;
+	    // Go type: time
+	    last_processed_time: any;
 	
 	    static createFrom(source: any = {}) {
 	        return new Stats(source);
@@ -150,6 +739,55 @@ export namespace main {
 	        this.total_size_bytes = source["total_size_bytes"];
 	        this.start_time = this.convertValues(source["start_time"], null);
 	        this.last_update_time = this.convertValues(source["last_update_time"], null);
+	        this.last_processed_time = this.convertValues(source["last_processed_time"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class WatchEntry {
+	    id: string;
+	    name: string;
+	    source: string;
+	    path: string;
+	    enabled: boolean;
+	    recursive: boolean;
+	    backend: string;
+	    filter: clipfilter.Filter;
+	    destination: string;
+	    auto_send: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new WatchEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.source = source["source"];
+	        this.path = source["path"];
+	        this.enabled = source["enabled"];
+	        this.recursive = source["recursive"];
+	        this.backend = source["backend"];
+	        this.filter = this.convertValues(source["filter"], clipfilter.Filter);
+	        this.destination = source["destination"];
+	        this.auto_send = source["auto_send"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
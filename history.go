@@ -0,0 +1,300 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"autoclipsend/logger"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// How a send attempt ended
+const (
+	OutcomeSent      = "sent"      // Every destination received the clip
+	OutcomePartial   = "partial"   // Some destinations failed
+	OutcomeFailed    = "failed"    // Nothing was delivered
+	OutcomeCancelled = "cancelled" // Stopped through CancelSend
+	OutcomeDeleted   = "deleted"   // Posts of an earlier record taken down again, listed in Destinations
+)
+
+// defaultHistoryPageSize is used when a query doesn't set a limit
+const defaultHistoryPageSize = 50
+
+// StageTiming is how long one step of a send took
+type StageTiming struct {
+	Name    string  `json:"name"` // "extract", "compress" or "upload"
+	Seconds float64 `json:"seconds"`
+}
+
+// SendRecord is one attempt at sending a clip, as kept in the history
+type SendRecord struct {
+	JobID        string              `json:"jobId"`
+	Attempt      int                 `json:"attempt"`
+	FilePath     string              `json:"filePath"`
+	FileName     string              `json:"fileName"`
+	Source       string              `json:"source"`
+	GameTitle    string              `json:"gameTitle"`
+	AudioOnly    bool                `json:"audioOnly"`
+	Destinations []DestinationResult `json:"destinations"`
+	OriginalSize int64               `json:"originalSize"`
+	FinalSize    int64               `json:"finalSize"` // Size of what was uploaded
+	Strategy     string              `json:"strategy"`  // How the file was made to fit, "original" if it already did
+	Stages       []StageTiming       `json:"stages"`
+	Outcome      string              `json:"outcome"`
+	Error        string              `json:"error,omitempty"`
+	StartedAt    time.Time           `json:"startedAt"`
+	FinishedAt   time.Time           `json:"finishedAt"`
+}
+
+// stage records how long a step took, measured from start
+func (r *SendRecord) stage(name string, start time.Time) {
+	r.Stages = append(r.Stages, StageTiming{Name: name, Seconds: time.Since(start).Seconds()})
+}
+
+// HistoryQuery selects and pages send records. Empty fields don't filter.
+type HistoryQuery struct {
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	Game    string    `json:"game"`    // Game title, case-insensitive
+	Outcome string    `json:"outcome"` // One of the Outcome constants
+	Search  string    `json:"search"`  // Matched against file name, path, game and destination names
+	Offset  int       `json:"offset"`
+	Limit   int       `json:"limit"`
+}
+
+// HistoryPage is one page of query results, newest first
+type HistoryPage struct {
+	Records []SendRecord `json:"records"`
+	Total   int          `json:"total"` // Matching records across all pages
+}
+
+// matches reports whether the record passes every filter of the query
+func (q HistoryQuery) matches(r SendRecord) bool {
+	if !q.From.IsZero() && r.StartedAt.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && r.StartedAt.After(q.To) {
+		return false
+	}
+	if q.Game != "" && !strings.EqualFold(strings.TrimSpace(q.Game), strings.TrimSpace(r.GameTitle)) {
+		return false
+	}
+	if q.Outcome != "" && q.Outcome != r.Outcome {
+		return false
+	}
+	if q.Search != "" {
+		search := strings.ToLower(q.Search)
+		fields := []string{r.FileName, r.FilePath, r.GameTitle, r.Error}
+		for _, d := range r.Destinations {
+			fields = append(fields, d.Name)
+		}
+		found := false
+		for _, f := range fields {
+			if strings.Contains(strings.ToLower(f), search) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// History is an append-only log of send attempts, one JSON record per line.
+// Records are never rewritten, so a crash can at most lose the line being written.
+type History struct {
+	path string
+
+	mu      sync.Mutex
+	records []SendRecord
+}
+
+// NewHistory creates a history stored at path
+func NewHistory(path string) *History {
+	return &History{path: path}
+}
+
+// Load reads the records written by previous runs, skipping damaged lines
+func (h *History) Load() error {
+	f, err := os.Open(h.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var records []SendRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	skipped := 0
+	for scanner.Scan() {
+		var r SendRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			skipped++
+			continue
+		}
+		records = append(records, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if skipped > 0 {
+		logger.Warn("Skipped %d unreadable send history lines", skipped)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = records
+	return nil
+}
+
+// Append adds a record to the end of the log
+func (h *History) Append(r SendRecord) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	f, err := os.OpenFile(h.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	h.records = append(h.records, r)
+	return nil
+}
+
+// Query returns the page of records matching q, newest first
+func (h *History) Query(q HistoryQuery) HistoryPage {
+	h.mu.Lock()
+	var matched []SendRecord
+	for _, r := range h.records {
+		if q.matches(r) {
+			matched = append(matched, r)
+		}
+	}
+	h.mu.Unlock()
+
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].StartedAt.After(matched[j].StartedAt) })
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultHistoryPageSize
+	}
+	page := HistoryPage{Records: []SendRecord{}, Total: len(matched)}
+	if q.Offset < 0 || q.Offset >= len(matched) {
+		return page
+	}
+	end := q.Offset + limit
+	if end > len(matched) {
+		end = len(matched)
+	}
+	page.Records = matched[q.Offset:end]
+	return page
+}
+
+// Latest returns the newest record for which match is true
+func (h *History) Latest(match func(SendRecord) bool) (SendRecord, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i := len(h.records) - 1; i >= 0; i-- {
+		if match(h.records[i]) {
+			return h.records[i], true
+		}
+	}
+	return SendRecord{}, false
+}
+
+// Deleted reports whether a posted message was taken down through the app
+func (h *History) Deleted(messageID string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, r := range h.records {
+		if r.Outcome != OutcomeDeleted {
+			continue
+		}
+		for _, d := range r.Destinations {
+			if d.MessageID == messageID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Games returns every game title in the history, for filter menus
+func (h *History) Games() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	seen := make(map[string]bool)
+	var games []string
+	for _, r := range h.records {
+		if r.GameTitle != "" && !seen[strings.ToLower(r.GameTitle)] {
+			seen[strings.ToLower(r.GameTitle)] = true
+			games = append(games, r.GameTitle)
+		}
+	}
+	sort.Strings(games)
+	return games
+}
+
+// recordSend finishes a record from the attempt's result and appends it to the history
+func (a *App) recordSend(record *SendRecord, err error) {
+	record.FinishedAt = time.Now()
+	switch {
+	case errors.Is(err, context.Canceled):
+		record.Outcome = OutcomeCancelled
+	case err == nil:
+		record.Outcome = OutcomeSent
+	default:
+		record.Outcome = OutcomeFailed
+		for _, d := range record.Destinations {
+			if d.Success {
+				record.Outcome = OutcomePartial
+				break
+			}
+		}
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	a.appendHistory(*record)
+}
+
+// appendHistory writes a record and tells the history page about it
+func (a *App) appendHistory(record SendRecord) {
+	if err := a.history.Append(record); err != nil {
+		logger.Error("Failed to write send history: %v", err)
+		return
+	}
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "sendHistoryUpdated")
+	}
+}
+
+// QueryHistory pages through past sends, filtered by date, game, outcome or a search term
+func (a *App) QueryHistory(query HistoryQuery) HistoryPage {
+	return a.history.Query(query)
+}
+
+// GetHistoryGames lists the games that appear in the send history
+func (a *App) GetHistoryGames() []string {
+	return a.history.Games()
+}
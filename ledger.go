@@ -0,0 +1,300 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"autoclipsend/logger"
+)
+
+// What was uploaded for a clip
+const (
+	VariantVideo      = "video"      // The original file
+	VariantCompressed = "compressed" // A re-encoded copy that fits the size limit
+	VariantAudio      = "audio"      // Audio extracted from the clip
+	VariantSplit      = "split"      // The original cut into several parts
+)
+
+// What happens when a clip is sent to a destination that already has it
+const (
+	DuplicateWarn   = "warn"   // Log and report it, then send anyway (default)
+	DuplicateRefuse = "refuse" // Skip destinations that already received the clip
+	DuplicateAllow  = "allow"  // Send without checking
+)
+
+// SentEntry records one clip delivered to one destination
+type SentEntry struct {
+	Hash            string    `json:"hash"` // SHA-256 of the original clip
+	FilePath        string    `json:"filePath"`
+	Size            int64     `json:"size"`
+	ModTime         time.Time `json:"modTime"`
+	DestinationID   string    `json:"destinationId"`
+	DestinationName string    `json:"destinationName"`
+	MessageID       string    `json:"messageId,omitempty"`
+	ThreadID        string    `json:"threadId,omitempty"` // Forum post or thread the clip was posted in
+	SentAt          time.Time `json:"sentAt"`
+	Variant         string    `json:"variant"`
+}
+
+// audio reports whether the entry is an audio-only send, which doesn't count
+// as a duplicate of the video and vice versa
+func (e SentEntry) audio() bool {
+	return e.Variant == VariantAudio
+}
+
+// fileKey identifies an unchanged file without reading it
+type fileKey struct {
+	path    string
+	size    int64
+	modTime int64
+}
+
+func newFileKey(path string, info os.FileInfo) fileKey {
+	return fileKey{strings.ToLower(filepath.Clean(path)), info.Size(), info.ModTime().UnixNano()}
+}
+
+// Ledger remembers every clip that was sent, keyed by content hash so renamed
+// or re-synced copies are still recognised. Path, size and modification time
+// are checked first so unchanged files are never hashed twice.
+type Ledger struct {
+	path string
+
+	mu      sync.Mutex
+	entries []SentEntry
+	hashes  map[fileKey]string // Known hashes, from entries and this session
+	sizes   map[int64]bool     // Sizes of sent clips, to skip hashing files that can't match
+}
+
+// NewLedger creates a ledger persisted at path
+func NewLedger(path string) *Ledger {
+	return &Ledger{
+		path:   path,
+		hashes: make(map[fileKey]string),
+		sizes:  make(map[int64]bool),
+	}
+}
+
+// Load restores the entries saved by previous runs
+func (l *Ledger) Load() error {
+	data, err := os.ReadFile(l.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var entries []SentEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("failed to parse sent clip ledger: %v", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = entries
+	for _, e := range entries {
+		l.indexLocked(e)
+	}
+	return nil
+}
+
+// Hash returns the content hash of a file, reading it only if it changed
+// since it was last hashed
+func (l *Ledger) Hash(filePath string) (string, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return "", err
+	}
+	key := newFileKey(filePath, info)
+
+	l.mu.Lock()
+	hash, ok := l.hashes[key]
+	l.mu.Unlock()
+	if ok {
+		return hash, nil
+	}
+
+	hash, err = hashFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	l.mu.Lock()
+	l.hashes[key] = hash
+	l.mu.Unlock()
+	return hash, nil
+}
+
+// CandidateHash returns the hash to look a file up in the ledger with. It is
+// answered from path, size and modification time when the file was hashed
+// before, and is empty without reading the file when no sent clip has its
+// size, since then no entry can share its hash.
+func (l *Ledger) CandidateHash(filePath string) (string, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	l.mu.Lock()
+	hash, known := l.hashes[newFileKey(filePath, info)]
+	possible := l.sizes[info.Size()]
+	l.mu.Unlock()
+	if known {
+		return hash, nil
+	}
+	if !possible {
+		return "", nil
+	}
+	return l.Hash(filePath)
+}
+
+// Find returns every send of the content with the given hash
+func (l *Ledger) Find(hash string) []SentEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var found []SentEntry
+	for _, e := range l.entries {
+		if e.Hash == hash {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// IsSent reports whether a file, or a copy of it, was sent before. Files whose
+// size matches no sent clip are answered without hashing.
+func (l *Ledger) IsSent(filePath string) bool {
+	hash, err := l.CandidateHash(filePath)
+	if err != nil || hash == "" {
+		return false
+	}
+	return len(l.Find(hash)) > 0
+}
+
+// Record adds a send to the ledger and saves it
+func (l *Ledger) Record(entry SentEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, entry)
+	l.indexLocked(entry)
+	l.saveLocked()
+}
+
+// Forget drops the sends of a message that was deleted, so the clip can be posted again
+func (l *Ledger) Forget(messageID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	kept := l.entries[:0]
+	for _, e := range l.entries {
+		if e.MessageID != messageID {
+			kept = append(kept, e)
+		}
+	}
+	if len(kept) == len(l.entries) {
+		return
+	}
+	l.entries = kept
+	l.sizes = make(map[int64]bool)
+	for _, e := range l.entries {
+		l.sizes[e.Size] = true
+	}
+	l.saveLocked()
+}
+
+func (l *Ledger) indexLocked(e SentEntry) {
+	key := fileKey{strings.ToLower(filepath.Clean(e.FilePath)), e.Size, e.ModTime.UnixNano()}
+	l.hashes[key] = e.Hash
+	l.sizes[e.Size] = true
+}
+
+func (l *Ledger) saveLocked() {
+	data, err := json.MarshalIndent(l.entries, "", "  ")
+	if err != nil {
+		logger.Error("Failed to encode sent clip ledger: %v", err)
+		return
+	}
+
+	tmpPath := l.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		logger.Error("Failed to write sent clip ledger: %v", err)
+		return
+	}
+	if err := os.Rename(tmpPath, l.path); err != nil {
+		logger.Error("Failed to save sent clip ledger: %v", err)
+	}
+}
+
+// hashFile computes the SHA-256 of a file's contents
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// checkDuplicates applies the duplicate policy to the destinations of a send.
+// It returns the destinations still to send to and a note for the user when
+// some of them already have the clip.
+func (a *App) checkDuplicates(hash, fileName string, audioOnly bool, destinations []Destination) ([]Destination, string) {
+	policy := a.configManager.Snapshot().DuplicatePolicy
+	if policy == DuplicateAllow || hash == "" {
+		return destinations, ""
+	}
+
+	previous := make(map[string]SentEntry)
+	for _, e := range a.ledger.Find(hash) {
+		if e.audio() == audioOnly {
+			previous[e.DestinationID] = e
+		}
+	}
+
+	var keep []Destination
+	var names []string
+	for _, dest := range destinations {
+		e, dup := previous[dest.ID]
+		if !dup {
+			keep = append(keep, dest)
+			continue
+		}
+		names = append(names, dest.Name)
+		logger.Warn("%s was already sent to %s on %s", fileName, dest.Name, e.SentAt.Format(time.RFC1123))
+		if policy != DuplicateRefuse {
+			keep = append(keep, dest)
+		}
+	}
+	if len(names) == 0 {
+		return keep, ""
+	}
+	if policy == DuplicateRefuse {
+		return keep, fmt.Sprintf("Already sent to %s, skipping", strings.Join(names, ", "))
+	}
+	return keep, fmt.Sprintf("Already sent to %s, sending again", strings.Join(names, ", "))
+}
+
+// IsClipSent reports whether a file, or an identical copy of it, was sent before
+func (a *App) IsClipSent(filePath string) bool {
+	return a.ledger.IsSent(filePath)
+}
+
+// GetClipSends lists where and when a file's content was sent
+func (a *App) GetClipSends(filePath string) ([]SentEntry, error) {
+	hash, err := a.ledger.CandidateHash(filePath)
+	if err != nil || hash == "" {
+		return nil, err
+	}
+	return a.ledger.Find(hash), nil
+}
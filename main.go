@@ -64,6 +64,7 @@ func main() {
 		BackgroundColour:  &options.RGBA{R: 27, G: 38, B: 54, A: 1},
 		OnStartup:         app.startup,
 		OnBeforeClose:     app.beforeClose,
+		OnShutdown:        app.shutdown,
 		HideWindowOnClose: true,                                        // Set to true to ensure window hides instead of closing
 		Bind:              []interface{}{app, app.notificationHandler}, // <-- Bind the app struct and notification handler for Wails
 		Frameless:         false,                                       // Use system title bar instead of custom topbar
@@ -0,0 +1,236 @@
+package media
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// FFmpeg is the Transcoder backed by the ffmpeg and ffprobe executables
+type FFmpeg struct {
+	FFmpegPath  string
+	FFprobePath string
+}
+
+// NewFFmpeg returns a Transcoder that finds ffmpeg and ffprobe on PATH
+func NewFFmpeg() *FFmpeg {
+	return &FFmpeg{FFmpegPath: "ffmpeg", FFprobePath: "ffprobe"}
+}
+
+// ffprobeOutput mirrors the parts of `ffprobe -of json` that Probe uses
+type ffprobeOutput struct {
+	Streams []struct {
+		Index        int    `json:"index"`
+		CodecType    string `json:"codec_type"`
+		CodecName    string `json:"codec_name"`
+		Width        int    `json:"width"`
+		Height       int    `json:"height"`
+		AvgFrameRate string `json:"avg_frame_rate"`
+		BitRate      string `json:"bit_rate"`
+		Channels     int    `json:"channels"`
+	} `json:"streams"`
+	Format struct {
+		FormatName string `json:"format_name"`
+		Duration   string `json:"duration"`
+		BitRate    string `json:"bit_rate"`
+		Size       string `json:"size"`
+	} `json:"format"`
+}
+
+// Probe runs ffprobe on path
+func (f *FFmpeg) Probe(ctx context.Context, path string) (*Probe, error) {
+	cmd := exec.CommandContext(ctx, f.FFprobePath, "-v", "quiet", "-show_streams", "-show_format", "-of", "json", path)
+	hideWindow(cmd)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseProbe(output)
+}
+
+func parseProbe(output []byte) (*Probe, error) {
+	var raw ffprobeOutput
+	if err := json.Unmarshal(output, &raw); err != nil {
+		return nil, fmt.Errorf("invalid ffprobe output: %v", err)
+	}
+
+	probe := &Probe{Container: raw.Format.FormatName}
+	probe.Duration, _ = strconv.ParseFloat(raw.Format.Duration, 64)
+	probe.BitRate, _ = strconv.ParseInt(raw.Format.BitRate, 10, 64)
+	probe.Size, _ = strconv.ParseInt(raw.Format.Size, 10, 64)
+
+	for _, s := range raw.Streams {
+		stream := Stream{
+			Index:    s.Index,
+			Type:     s.CodecType,
+			Codec:    s.CodecName,
+			Width:    s.Width,
+			Height:   s.Height,
+			Channels: s.Channels,
+		}
+		if s.CodecType == StreamVideo {
+			stream.FPS = parseFrameRate(s.AvgFrameRate)
+		}
+		stream.BitRate, _ = strconv.ParseInt(s.BitRate, 10, 64)
+		probe.Streams = append(probe.Streams, stream)
+	}
+	return probe, nil
+}
+
+// parseFrameRate converts ffprobe's "60000/1001" style rates into a number
+func parseFrameRate(rate string) float64 {
+	num, den, found := strings.Cut(rate, "/")
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0
+	}
+	if !found {
+		return n
+	}
+	d, err := strconv.ParseFloat(den, 64)
+	if err != nil || d == 0 {
+		return 0
+	}
+	return n / d
+}
+
+// EncodeAudio encodes the audio of input into output
+func (f *FFmpeg) EncodeAudio(ctx context.Context, input, output string, opts AudioOptions, progress ProgressFunc) error {
+	args := []string{"-y", "-i", input, "-vn"}
+	args = append(args, audioArgs(opts)...)
+	args = append(args, output)
+	return f.run(ctx, progress, args)
+}
+
+// EncodeVideo encodes input into output following opts
+func (f *FFmpeg) EncodeVideo(ctx context.Context, input, output string, opts VideoOptions, progress ProgressFunc) error {
+	args := []string{"-y", "-i", input}
+	args = append(args, videoArgs(opts)...)
+
+	if opts.Pass == 1 {
+		// The first pass only writes the stats file
+		args = append(args, "-an", "-f", "null", os.DevNull)
+		return f.run(ctx, progress, args)
+	}
+
+	if opts.Audio != nil {
+		args = append(args, audioArgs(*opts.Audio)...)
+	} else {
+		args = append(args, "-an")
+	}
+	if opts.FastStart {
+		args = append(args, "-movflags", "+faststart")
+	}
+	args = append(args, output)
+	return f.run(ctx, progress, args)
+}
+
+// Segment splits input with ffmpeg's segment muxer, copying the first video
+// and any audio stream
+func (f *FFmpeg) Segment(ctx context.Context, input, outputPattern string, times []float64, progress ProgressFunc) ([]string, error) {
+	cuts := make([]string, len(times))
+	for i, t := range times {
+		cuts[i] = strconv.FormatFloat(t, 'f', 3, 64)
+	}
+
+	args := []string{"-y", "-i", input, "-map", "0:v:0", "-map", "0:a?", "-c", "copy",
+		"-f", "segment", "-segment_times", strings.Join(cuts, ","), "-reset_timestamps", "1"}
+	switch strings.ToLower(filepath.Ext(outputPattern)) {
+	case ".mp4", ".mov", ".m4v":
+		// Only the MP4 muxer knows movflags, other containers reject the option
+		args = append(args, "-segment_format_options", "movflags=+faststart")
+	}
+	args = append(args, outputPattern)
+	if err := f.run(ctx, progress, args); err != nil {
+		return nil, err
+	}
+
+	var parts []string
+	for i := 0; ; i++ {
+		part := fmt.Sprintf(outputPattern, i)
+		if _, err := os.Stat(part); err != nil {
+			break
+		}
+		parts = append(parts, part)
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("ffmpeg wrote no segments")
+	}
+	return parts, nil
+}
+
+func videoArgs(opts VideoOptions) []string {
+	args := []string{"-c:v", opts.Codec}
+	if opts.Preset != "" {
+		args = append(args, "-preset", opts.Preset)
+	}
+	if opts.Bitrate > 0 {
+		args = append(args, "-b:v", strconv.FormatInt(opts.Bitrate, 10))
+	} else if opts.CRF > 0 {
+		args = append(args, "-crf", strconv.Itoa(opts.CRF))
+	}
+	if opts.Pass > 0 {
+		args = append(args, "-pass", strconv.Itoa(opts.Pass))
+		if opts.PassLogFile != "" {
+			args = append(args, "-passlogfile", opts.PassLogFile)
+		}
+	}
+
+	var filters []string
+	if opts.Height > 0 {
+		filters = append(filters, fmt.Sprintf("scale=-2:%d", opts.Height))
+	} else if opts.Scale > 0 && opts.Scale != 1 {
+		// Keep dimensions even, which yuv420p requires
+		filters = append(filters, fmt.Sprintf("scale=trunc(iw*%g/2)*2:trunc(ih*%g/2)*2", opts.Scale, opts.Scale))
+	}
+	if opts.FPS > 0 {
+		filters = append(filters, fmt.Sprintf("fps=%g", opts.FPS))
+	}
+	if len(filters) > 0 {
+		args = append(args, "-vf", strings.Join(filters, ","))
+	}
+	return args
+}
+
+func audioArgs(opts AudioOptions) []string {
+	args := []string{"-c:a", opts.Codec}
+	if opts.Bitrate > 0 {
+		args = append(args, "-b:a", strconv.FormatInt(opts.Bitrate, 10))
+	}
+	if opts.SampleRate > 0 {
+		args = append(args, "-ar", strconv.Itoa(opts.SampleRate))
+	}
+	if opts.Channels > 0 {
+		args = append(args, "-ac", strconv.Itoa(opts.Channels))
+	}
+	return args
+}
+
+// run executes ffmpeg, feeding its -progress output to progress when set
+func (f *FFmpeg) run(ctx context.Context, progress ProgressFunc, args []string) error {
+	if progress != nil {
+		args = append([]string{"-progress", "pipe:1", "-nostats"}, args...)
+	}
+	cmd := exec.CommandContext(ctx, f.FFmpegPath, args...)
+	hideWindow(cmd)
+
+	if progress == nil {
+		return cmd.Run()
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	ReadProgress(stdout, progress)
+	return cmd.Wait()
+}
@@ -0,0 +1,170 @@
+// Package mediatest provides a media.Transcoder for tests that writes output
+// files of a chosen size instead of running ffmpeg.
+package mediatest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"autoclipsend/media"
+)
+
+// Call records one request made to the fake
+type Call struct {
+	Method string // "Probe", "EncodeAudio", "EncodeVideo" or "Segment"
+	Input  string
+	Output string
+	Video  media.VideoOptions
+	Audio  media.AudioOptions
+	Times  []float64
+}
+
+// Transcoder is a fake media.Transcoder. Unset hooks write empty files.
+type Transcoder struct {
+	// ProbeResult is returned by Probe, ProbeErr takes precedence when set
+	ProbeResult *media.Probe
+	ProbeErr    error
+
+	// VideoSize and AudioSize give the size of the file an encode writes.
+	// attempt counts the encodes that wrote output, starting at 1.
+	VideoSize func(opts media.VideoOptions, attempt int) int64
+	AudioSize func(opts media.AudioOptions, attempt int) int64
+
+	// SegmentSizes gives the size of each part Segment writes, the rest are empty
+	SegmentSizes []int64
+
+	// Err fails every encode and segment call when set
+	Err error
+
+	mu       sync.Mutex
+	calls    []Call
+	attempts int
+}
+
+var _ media.Transcoder = (*Transcoder)(nil)
+
+// Clip returns a probe result for a clip of duration seconds. A height of 0
+// leaves out the video stream and a negative audioBitrate the audio stream.
+func Clip(duration float64, width, height int, fps float64, audioBitrate int64) *media.Probe {
+	probe := &media.Probe{Duration: duration}
+	if height > 0 {
+		probe.Streams = append(probe.Streams, media.Stream{Type: media.StreamVideo, Width: width, Height: height, FPS: fps})
+	}
+	if audioBitrate >= 0 {
+		probe.Streams = append(probe.Streams, media.Stream{Type: media.StreamAudio, BitRate: audioBitrate})
+	}
+	return probe
+}
+
+// Calls returns the requests made so far, in order
+func (t *Transcoder) Calls() []Call {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Call(nil), t.calls...)
+}
+
+func (t *Transcoder) record(c Call) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.calls = append(t.calls, c)
+	if c.Method != "Probe" && !(c.Method == "EncodeVideo" && c.Video.Pass == 1) {
+		t.attempts++
+	}
+	return t.attempts
+}
+
+// Probe returns ProbeResult
+func (t *Transcoder) Probe(ctx context.Context, path string) (*media.Probe, error) {
+	t.record(Call{Method: "Probe", Input: path})
+	if t.ProbeErr != nil {
+		return nil, t.ProbeErr
+	}
+	if t.ProbeResult == nil {
+		return nil, fmt.Errorf("no probe result for %s", path)
+	}
+	probe := *t.ProbeResult
+	return &probe, nil
+}
+
+// EncodeAudio writes a file of AudioSize bytes to output
+func (t *Transcoder) EncodeAudio(ctx context.Context, input, output string, opts media.AudioOptions, progress media.ProgressFunc) error {
+	attempt := t.record(Call{Method: "EncodeAudio", Input: input, Output: output, Audio: opts})
+	if err := t.run(ctx, progress); err != nil {
+		return err
+	}
+	var size int64
+	if t.AudioSize != nil {
+		size = t.AudioSize(opts, attempt)
+	}
+	return writeFile(output, size)
+}
+
+// EncodeVideo writes a file of VideoSize bytes to output. The first pass of
+// a two-pass encode writes nothing, like ffmpeg.
+func (t *Transcoder) EncodeVideo(ctx context.Context, input, output string, opts media.VideoOptions, progress media.ProgressFunc) error {
+	attempt := t.record(Call{Method: "EncodeVideo", Input: input, Output: output, Video: opts})
+	if err := t.run(ctx, progress); err != nil {
+		return err
+	}
+	if opts.Pass == 1 {
+		return nil
+	}
+	var size int64
+	if t.VideoSize != nil {
+		size = t.VideoSize(opts, attempt)
+	}
+	return writeFile(output, size)
+}
+
+// Segment writes len(times)+1 parts named by outputPattern
+func (t *Transcoder) Segment(ctx context.Context, input, outputPattern string, times []float64, progress media.ProgressFunc) ([]string, error) {
+	t.record(Call{Method: "Segment", Input: input, Output: outputPattern, Times: times})
+	if err := t.run(ctx, progress); err != nil {
+		return nil, err
+	}
+	var parts []string
+	for i := 0; i <= len(times); i++ {
+		part := fmt.Sprintf(outputPattern, i)
+		var size int64
+		if i < len(t.SegmentSizes) {
+			size = t.SegmentSizes[i]
+		}
+		if err := writeFile(part, size); err != nil {
+			return parts, err
+		}
+		parts = append(parts, part)
+	}
+	return parts, nil
+}
+
+// run reports the start and end of an encode, then fails it if asked to
+func (t *Transcoder) run(ctx context.Context, progress media.ProgressFunc) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if progress != nil {
+		var duration float64
+		if t.ProbeResult != nil {
+			duration = t.ProbeResult.Duration
+		}
+		progress(0, 0)
+		progress(time.Duration(duration*float64(time.Second)), 1)
+	}
+	return t.Err
+}
+
+// writeFile creates path with size bytes, sparse where the filesystem allows
+func writeFile(path string, size int64) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := f.Truncate(size); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
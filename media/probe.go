@@ -0,0 +1,59 @@
+package media
+
+// Stream types reported by Probe
+const (
+	StreamVideo = "video"
+	StreamAudio = "audio"
+)
+
+// Stream describes one track of a media file
+type Stream struct {
+	Index    int
+	Type     string // StreamVideo, StreamAudio, "subtitle", ...
+	Codec    string
+	Width    int
+	Height   int
+	FPS      float64
+	BitRate  int64 // bits per second, 0 if unknown
+	Channels int
+}
+
+// Probe is what is known about a media file without decoding it
+type Probe struct {
+	Duration  float64 // seconds
+	Container string  // ffprobe format name, e.g. "mov,mp4,m4a,3gp,3g2,mj2"
+	BitRate   int64   // Overall bits per second
+	Size      int64
+	Streams   []Stream
+}
+
+// Video returns the first video stream, or nil for audio-only files
+func (p *Probe) Video() *Stream {
+	for i := range p.Streams {
+		if p.Streams[i].Type == StreamVideo {
+			return &p.Streams[i]
+		}
+	}
+	return nil
+}
+
+// Audio returns the first audio stream, or nil if there is none
+func (p *Probe) Audio() *Stream {
+	for i := range p.Streams {
+		if p.Streams[i].Type == StreamAudio {
+			return &p.Streams[i]
+		}
+	}
+	return nil
+}
+
+// AudioTracks counts the audio streams, e.g. game and microphone recorded separately
+func (p *Probe) AudioTracks() int {
+	count := 0
+	for _, s := range p.Streams {
+		if s.Type == StreamAudio {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package media
+
+import "os/exec"
+
+// hideWindow is only needed on Windows
+func hideWindow(cmd *exec.Cmd) {}
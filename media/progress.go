@@ -0,0 +1,37 @@
+package media
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProgressFunc receives how much of the input has been encoded so far and
+// the encode speed as a multiple of real time (0 when not known yet)
+type ProgressFunc func(outTime time.Duration, speed float64)
+
+// ReadProgress parses ffmpeg's -progress output: blocks of key=value lines,
+// each terminated by progress=continue or progress=end
+func ReadProgress(r io.Reader, progress ProgressFunc) {
+	var outTime time.Duration
+	var speed float64
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "out_time_us", "out_time_ms": // Both are microseconds, out_time_ms is misnamed
+			if us, err := strconv.ParseInt(value, 10, 64); err == nil && us >= 0 {
+				outTime = time.Duration(us) * time.Microsecond
+			}
+		case "speed":
+			speed, _ = strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "x"), 64)
+		case "progress":
+			progress(outTime, speed)
+		}
+	}
+}
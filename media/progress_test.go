@@ -0,0 +1,69 @@
+package media
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type progressReport struct {
+	outTime time.Duration
+	speed   float64
+}
+
+func TestReadProgress(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []progressReport
+	}{
+		{
+			name:   "empty",
+			output: "",
+			want:   nil,
+		},
+		{
+			name: "one block per report",
+			output: "frame=10\nout_time_us=1500000\nspeed=2.5x\nprogress=continue\n" +
+				"frame=20\nout_time_us=3000000\nspeed=2.25x\nprogress=end\n",
+			want: []progressReport{{1500 * time.Millisecond, 2.5}, {3 * time.Second, 2.25}},
+		},
+		{
+			name:   "out_time_ms is microseconds too",
+			output: "out_time_ms=2000000\nprogress=continue\n",
+			want:   []progressReport{{2 * time.Second, 0}},
+		},
+		{
+			name:   "speed not known yet",
+			output: "out_time_us=0\nspeed=N/A\nprogress=continue\n",
+			want:   []progressReport{{0, 0}},
+		},
+		{
+			name:   "negative and invalid times keep the last value",
+			output: "out_time_us=1000000\nprogress=continue\nout_time_us=-5\nprogress=continue\nout_time_us=abc\nprogress=continue\n",
+			want:   []progressReport{{time.Second, 0}, {time.Second, 0}, {time.Second, 0}},
+		},
+		{
+			name:   "whitespace and lines without a value",
+			output: "  out_time_us=4000000 \r\ngarbage\n speed= 1.0x\nprogress=end\r\n",
+			want:   []progressReport{{4 * time.Second, 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []progressReport
+			ReadProgress(strings.NewReader(tt.output), func(outTime time.Duration, speed float64) {
+				got = append(got, progressReport{outTime, speed})
+			})
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d reports %v, want %d %v", len(got), got, len(tt.want), tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("report %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
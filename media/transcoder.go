@@ -0,0 +1,51 @@
+// Package media wraps the external tools used to inspect and re-encode clips.
+// The app only talks to the Transcoder interface so the ffmpeg backend can be
+// swapped out or faked.
+package media
+
+import (
+	"context"
+)
+
+// Transcoder probes and re-encodes media files
+type Transcoder interface {
+	// Probe reads container and stream details of a file
+	Probe(ctx context.Context, path string) (*Probe, error)
+	// EncodeAudio writes the first audio track of input to output, dropping any video
+	EncodeAudio(ctx context.Context, input, output string, opts AudioOptions, progress ProgressFunc) error
+	// EncodeVideo re-encodes input to output. For the first pass of a
+	// two-pass encode output is ignored and nothing is written.
+	EncodeVideo(ctx context.Context, input, output string, opts VideoOptions, progress ProgressFunc) error
+	// Segment copies input into parts without re-encoding, cutting at the first
+	// keyframe at or after each of times (in seconds). outputPattern holds one
+	// printf verb for the part number, starting at 0. The parts are returned in order.
+	Segment(ctx context.Context, input, outputPattern string, times []float64, progress ProgressFunc) ([]string, error)
+}
+
+// AudioOptions controls an audio-only encode
+type AudioOptions struct {
+	Codec      string // e.g. "mp3", "aac"
+	Bitrate    int64  // bits per second
+	SampleRate int    // Hz, 0 keeps the source rate
+	Channels   int    // 0 keeps the source layout
+}
+
+// VideoOptions controls a video encode. Zero values keep the source setting
+// or leave the choice to the encoder.
+type VideoOptions struct {
+	Codec   string // e.g. "libx264"
+	Preset  string
+	CRF     int   // Constant quality, used when Bitrate is 0
+	Bitrate int64 // Target video bits per second
+
+	// Two-pass encoding: Pass is 1 or 2 with a shared PassLogFile prefix, 0 for a single pass
+	Pass        int
+	PassLogFile string
+
+	Height int     // Output height, width follows the aspect ratio
+	Scale  float64 // Fraction of the source size, used when Height is 0
+	FPS    float64
+
+	Audio     *AudioOptions // nil drops the audio
+	FastStart bool          // Move the MP4 index to the front for streaming playback
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"autoclipsend/discord"
+)
+
+// defaultEmbedColor is the app's accent orange
+const defaultEmbedColor = 0xff7c3d
+
+// buildPayload creates the webhook message sent along with a clip
+func (a *App) buildPayload(dest Destination, clip ClipInfo, finalSize int64, caption string, audioOnly bool) discord.Payload {
+	config := a.configManager.Snapshot()
+	payload := discord.Payload{
+		Content:   caption,
+		Username:  firstNonEmpty(dest.Username, config.WebhookUsername),
+		AvatarURL: firstNonEmpty(dest.AvatarURL, config.WebhookAvatarURL),
+	}
+
+	if config.EmbedEnabled {
+		// The caption becomes the embed title instead of a separate line of text
+		payload.Content = ""
+		payload.Embeds = []discord.Embed{a.buildClipEmbed(clip, finalSize, caption, audioOnly)}
+	}
+
+	return payload
+}
+
+// buildClipEmbed describes the clip with its game, duration, size and resolution
+func (a *App) buildClipEmbed(clip ClipInfo, finalSize int64, caption string, audioOnly bool) discord.Embed {
+	embed := discord.Embed{
+		Color:  defaultEmbedColor,
+		Footer: &discord.EmbedFooter{Text: "Sent with AutoClipSend"},
+	}
+	if color, ok := discord.ParseColor(a.configManager.Snapshot().EmbedColor); ok {
+		embed.Color = color
+	}
+
+	embed.SetTitle(firstNonEmpty(caption, clip.Title, clip.FileName))
+	embed.AddField("Game", clip.GameTitle)
+	if clip.Duration > 0 {
+		embed.AddField("Duration", formatClipDuration(clip.Duration))
+	}
+	if clip.Width > 0 && clip.Height > 0 && !audioOnly {
+		embed.AddField("Resolution", fmt.Sprintf("%dx%d", clip.Width, clip.Height))
+	}
+	if finalSize > 0 && clip.Size > 0 && finalSize != clip.Size {
+		embed.AddField("Original Size", formatFileSize(clip.Size))
+		embed.AddField("Sent Size", formatFileSize(finalSize))
+	} else if finalSize > 0 {
+		embed.AddField("Size", formatFileSize(finalSize))
+	}
+	if !clip.RecordedAt.IsZero() {
+		// Discord renders <t:...> in each viewer's own timezone
+		embed.AddField("Recorded", fmt.Sprintf("<t:%d:f>", clip.RecordedAt.Unix()))
+		embed.Timestamp = clip.RecordedAt.UTC().Format(time.RFC3339)
+	}
+
+	return embed
+}
+
+// formatClipDuration formats seconds as m:ss, or h:mm:ss for long recordings
+func formatClipDuration(seconds float64) string {
+	total := int(seconds + 0.5)
+	h, m, s := total/3600, (total/60)%60, total%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", m, s)
+}
+
+// formatFileSize formats a byte count as MB, or KB for small files
+func formatFileSize(bytes int64) string {
+	if bytes < 1024*1024 {
+		return fmt.Sprintf("%.0f KB", float64(bytes)/1024)
+	}
+	return fmt.Sprintf("%.1f MB", float64(bytes)/(1024*1024))
+}
+
+// firstNonEmpty returns the first of values that isn't empty
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"autoclipsend/logger"
+	"autoclipsend/watcher"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// MonitorState is where the folder monitor is in its lifecycle
+type MonitorState string
+
+const (
+	MonitorStopped  MonitorState = "stopped"
+	MonitorStarting MonitorState = "starting" // Watchers are being created
+	MonitorRunning  MonitorState = "running"
+	MonitorPaused   MonitorState = "paused" // Watchers keep running, events and finished clips are held until resumed
+	MonitorError    MonitorState = "error"  // Start failed, see MonitorStatus.Error
+)
+
+// maxPausedEvents bounds how much a long pause can hold, older events are dropped first
+const maxPausedEvents = 10000
+
+// MonitorStatus is sent with every monitoring-state event
+type MonitorStatus struct {
+	State    MonitorState `json:"state"`
+	Error    string       `json:"error,omitempty"`
+	Paths    []string     `json:"paths"`
+	Buffered int          `json:"buffered"` // Events and finished clips held while paused
+}
+
+// Monitor owns the watchers and the goroutine dispatching their events. All
+// transitions go through its methods, which are safe to call from the tray,
+// bindings and startup concurrently.
+type Monitor struct {
+	app *App
+
+	mu        sync.Mutex
+	state     MonitorState
+	err       error
+	ctx       context.Context // Of the current or last run, cancelled by Stop
+	cancel    context.CancelFunc
+	done      chan struct{} // Closed when the event loop exits
+	buffered  []watcher.Event
+	ready     []string // Clips the stabilizer finished while paused
+	listeners []func(MonitorStatus)
+}
+
+// NewMonitor creates a stopped monitor for app
+func NewMonitor(app *App) *Monitor {
+	return &Monitor{app: app, state: MonitorStopped}
+}
+
+// Subscribe calls fn with the new status after every transition
+func (m *Monitor) Subscribe(fn func(MonitorStatus)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listeners = append(m.listeners, fn)
+}
+
+// State returns the current state
+func (m *Monitor) State() MonitorState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.state
+}
+
+// Active reports whether watchers are running, paused or not
+func (m *Monitor) Active() bool {
+	state := m.State()
+	return state == MonitorRunning || state == MonitorPaused || state == MonitorStarting
+}
+
+// Status returns the current state with details
+func (m *Monitor) Status() MonitorStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.statusLocked()
+}
+
+func (m *Monitor) statusLocked() MonitorStatus {
+	status := MonitorStatus{State: m.state, Buffered: len(m.buffered) + len(m.ready), Paths: m.app.GetMonitoredPaths()}
+	if m.err != nil {
+		status.Error = m.err.Error()
+	}
+	return status
+}
+
+// setStateLocked records a transition and notifies listeners and the frontend
+func (m *Monitor) setStateLocked(state MonitorState, err error) {
+	m.state, m.err = state, err
+	if err != nil {
+		logger.Error("Monitoring %s: %v", state, err)
+	} else {
+		logger.Info("Monitoring %s", state)
+	}
+
+	status := m.statusLocked()
+	for _, fn := range m.listeners {
+		go fn(status)
+	}
+	if m.app.ctx != nil {
+		runtime.EventsEmit(m.app.ctx, "monitoring-state", status)
+	}
+}
+
+// Start creates watchers for every enabled watch entry and starts dispatching
+// their events. Starting a paused monitor resumes it.
+func (m *Monitor) Start() error {
+	m.mu.Lock()
+	switch m.state {
+	case MonitorRunning, MonitorStarting:
+		m.mu.Unlock()
+		return nil
+	case MonitorPaused:
+		m.mu.Unlock()
+		m.Resume()
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	m.ctx, m.cancel, m.done = ctx, cancel, done
+	m.setStateLocked(MonitorStarting, nil)
+	m.mu.Unlock()
+
+	a := m.app
+	a.watcherMutex.Lock()
+	a.syncWatchersLocked()
+	count := len(a.watchers)
+	a.watcherMutex.Unlock()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if ctx.Err() != nil {
+		close(done) // Stopped while starting, Stop finishes the cleanup
+		return nil
+	}
+	if count == 0 {
+		cancel()
+		close(done)
+		m.stopWatchers()
+		err := errors.New("no folders could be watched")
+		m.setStateLocked(MonitorError, err)
+		return err
+	}
+
+	go m.loop(ctx, done)
+	logger.Info("File monitoring started for %d paths: %v", count, a.GetMonitoredPaths())
+	m.setStateLocked(MonitorRunning, nil)
+	return nil
+}
+
+// Stop closes every watcher and ends the event loop. Events and clips held by
+// a pause are discarded, the clips are offered again if they change later.
+func (m *Monitor) Stop() {
+	m.mu.Lock()
+	switch m.state {
+	case MonitorStopped:
+		m.mu.Unlock()
+		return
+	case MonitorError:
+		m.setStateLocked(MonitorStopped, nil)
+		m.mu.Unlock()
+		return
+	}
+	m.cancel()
+	done := m.done
+	m.mu.Unlock()
+
+	<-done
+	m.stopWatchers()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.done != done {
+		return // Started again in the meantime
+	}
+	m.buffered = nil
+	for _, path := range m.ready {
+		m.app.stabilizer.Forget(path)
+	}
+	m.ready = nil
+	m.setStateLocked(MonitorStopped, nil)
+}
+
+// Done is closed once the current run stops, goroutines feeding the event
+// loop give up then instead of blocking on it
+func (m *Monitor) Done() <-chan struct{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ctx == nil {
+		closed := make(chan struct{})
+		close(closed)
+		return closed
+	}
+	return m.ctx.Done()
+}
+
+// Restart stops and starts again, picking up configuration changes that need new watchers
+func (m *Monitor) Restart() error {
+	m.Stop()
+	return m.Start()
+}
+
+// Pause holds new events and finished clips until Resume, without closing the watchers
+func (m *Monitor) Pause() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.state == MonitorRunning {
+		m.setStateLocked(MonitorPaused, nil)
+	}
+}
+
+// Resume handles the events held during a pause and goes back to running
+func (m *Monitor) Resume() {
+	m.mu.Lock()
+	if m.state != MonitorPaused {
+		m.mu.Unlock()
+		return
+	}
+	held, ready := m.buffered, m.ready
+	m.buffered, m.ready = nil, nil
+	m.setStateLocked(MonitorRunning, nil)
+	m.mu.Unlock()
+
+	if len(ready) > 0 {
+		logger.Info("Offering %d clips finished while paused", len(ready))
+	}
+	for _, path := range ready {
+		m.app.handleNewVideo(path)
+	}
+	if len(held) > 0 {
+		logger.Info("Handling %d events held while paused", len(held))
+	}
+	for _, event := range held {
+		m.app.handleWatcherEvent(event)
+	}
+}
+
+// clipReady is the stabilizer's callback for a finished clip. It offers the
+// clip, or holds it until Resume while paused.
+func (m *Monitor) clipReady(path string) {
+	m.mu.Lock()
+	paused := m.state == MonitorPaused
+	if paused {
+		m.ready = append(m.ready, path)
+	}
+	m.mu.Unlock()
+
+	if !paused {
+		m.app.handleNewVideo(path)
+	}
+}
+
+// stopWatchers closes the watchers and drops clips still being written
+func (m *Monitor) stopWatchers() {
+	m.app.watcherMutex.Lock()
+	defer m.app.watcherMutex.Unlock()
+	m.app.stopAllWatchers()
+}
+
+// loop dispatches watcher events until ctx is cancelled
+func (m *Monitor) loop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	eventCount := 0
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Monitoring stopped after processing %d events", eventCount)
+			return
+		case event := <-m.app.watchEvents:
+			eventCount++
+			if eventCount%100 == 0 {
+				logger.Debug("Processed %d events so far", eventCount)
+			}
+
+			m.mu.Lock()
+			paused := m.state == MonitorPaused
+			if paused {
+				if len(m.buffered) >= maxPausedEvents {
+					m.buffered = m.buffered[1:]
+				}
+				m.buffered = append(m.buffered, event)
+			}
+			m.mu.Unlock()
+
+			if !paused {
+				m.app.handleWatcherEvent(event)
+			}
+		}
+	}
+}
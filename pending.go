@@ -0,0 +1,265 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"autoclipsend/logger"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// PendingClip is a detected clip waiting for the user to send, skip or snooze it
+type PendingClip struct {
+	FilePath     string    `json:"filePath"`
+	FileName     string    `json:"fileName"`
+	DetectedAt   time.Time `json:"detectedAt"`
+	SnoozedUntil time.Time `json:"snoozedUntil,omitempty"` // Hidden from the prompt until then
+	Transient    bool      `json:"transient,omitempty"`    // Shown this session only, e.g. the test notification
+}
+
+// snoozed reports whether the clip should stay out of the prompt at now
+func (c PendingClip) snoozed(now time.Time) bool {
+	return c.SnoozedUntil.After(now)
+}
+
+// PendingStore keeps detections in Go, so several clips arriving together are
+// all offered and none is lost when the window is hidden or reloaded. It is
+// saved next to the config so pending clips also survive a restart.
+type PendingStore struct {
+	path     string
+	onChange func()
+
+	mu    sync.Mutex
+	clips []PendingClip
+}
+
+// NewPendingStore creates a store persisted at path
+func NewPendingStore(path string) *PendingStore {
+	return &PendingStore{path: path}
+}
+
+// Load restores clips saved by a previous run, dropping files that are gone
+func (s *PendingStore) Load() error {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var clips []PendingClip
+	if err := json.Unmarshal(data, &clips); err != nil {
+		return fmt.Errorf("failed to parse pending clips: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clips = s.clips[:0]
+	for _, clip := range clips {
+		if _, err := os.Stat(clip.FilePath); err != nil {
+			logger.Info("Dropping pending clip %s: %v", clip.FileName, err)
+			continue
+		}
+		s.clips = append(s.clips, clip)
+	}
+	if len(s.clips) > 0 {
+		logger.Info("Restored %d pending clips", len(s.clips))
+	}
+	return nil
+}
+
+// Add records a new detection, returning false if the clip is already pending
+func (s *PendingStore) Add(filePath string) bool {
+	return s.add(filePath, false)
+}
+
+// AddTransient records a clip that is offered like a detection but never saved
+func (s *PendingStore) AddTransient(filePath string) bool {
+	return s.add(filePath, true)
+}
+
+func (s *PendingStore) add(filePath string, transient bool) bool {
+	s.mu.Lock()
+	if s.indexLocked(filePath) >= 0 {
+		s.mu.Unlock()
+		return false
+	}
+	s.clips = append(s.clips, PendingClip{
+		FilePath:   filePath,
+		FileName:   filepath.Base(filePath),
+		DetectedAt: time.Now(),
+		Transient:  transient,
+	})
+	s.saveLocked()
+	s.mu.Unlock()
+
+	s.changed()
+	return true
+}
+
+// List returns the pending clips, oldest detection first
+func (s *PendingStore) List() []PendingClip {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := append([]PendingClip(nil), s.clips...)
+	sort.SliceStable(list, func(i, j int) bool { return list[i].DetectedAt.Before(list[j].DetectedAt) })
+	return list
+}
+
+// Remove takes a clip out of the store
+func (s *PendingStore) Remove(filePath string) (PendingClip, bool) {
+	s.mu.Lock()
+	i := s.indexLocked(filePath)
+	if i < 0 {
+		s.mu.Unlock()
+		return PendingClip{}, false
+	}
+	clip := s.clips[i]
+	s.clips = append(s.clips[:i], s.clips[i+1:]...)
+	s.saveLocked()
+	s.mu.Unlock()
+
+	s.changed()
+	return clip, true
+}
+
+// Snooze hides a clip from the prompt until the given time
+func (s *PendingStore) Snooze(filePath string, until time.Time) error {
+	s.mu.Lock()
+	i := s.indexLocked(filePath)
+	if i < 0 {
+		s.mu.Unlock()
+		return errors.New("clip is not pending")
+	}
+	s.clips[i].SnoozedUntil = until
+	s.saveLocked()
+	s.mu.Unlock()
+
+	s.changed()
+	return nil
+}
+
+// NextWake returns when the earliest snooze ends, zero if nothing is snoozed
+func (s *PendingStore) NextWake() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var next time.Time
+	now := time.Now()
+	for _, clip := range s.clips {
+		if clip.snoozed(now) && (next.IsZero() || clip.SnoozedUntil.Before(next)) {
+			next = clip.SnoozedUntil
+		}
+	}
+	return next
+}
+
+func (s *PendingStore) indexLocked(filePath string) int {
+	for i, clip := range s.clips {
+		if clip.FilePath == filePath {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *PendingStore) saveLocked() {
+	saved := make([]PendingClip, 0, len(s.clips))
+	for _, clip := range s.clips {
+		if !clip.Transient {
+			saved = append(saved, clip)
+		}
+	}
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		logger.Error("Failed to encode pending clips: %v", err)
+		return
+	}
+
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		logger.Error("Failed to write pending clips: %v", err)
+		return
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		logger.Error("Failed to save pending clips: %v", err)
+	}
+}
+
+func (s *PendingStore) changed() {
+	if s.onChange != nil {
+		s.onChange()
+	}
+}
+
+// schedulePendingWake brings the window back when the next snooze ends
+func (a *App) schedulePendingWake() {
+	next := a.pending.NextWake()
+
+	a.pendingMutex.Lock()
+	defer a.pendingMutex.Unlock()
+	if a.pendingWake != nil {
+		a.pendingWake.Stop()
+		a.pendingWake = nil
+	}
+	if next.IsZero() {
+		return
+	}
+	a.pendingWake = time.AfterFunc(time.Until(next), func() {
+		logger.Info("Snoozed clips are due again")
+		a.emitPendingClips()
+		a.ShowFromTray()
+		a.schedulePendingWake()
+	})
+}
+
+// emitPendingClips tells the prompt which clips are waiting
+func (a *App) emitPendingClips() {
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "pendingClipsUpdated", a.pending.List())
+	}
+}
+
+// GetPendingClips returns every clip waiting for a decision, including snoozed ones
+func (a *App) GetPendingClips() []PendingClip {
+	return a.pending.List()
+}
+
+// SendPendingClip queues a pending clip and takes it out of the prompt
+func (a *App) SendPendingClip(filePath, customName string, audioOnly bool) (SendJob, error) {
+	job, err := a.QueueSend(filePath, customName, audioOnly)
+	if err != nil {
+		return SendJob{}, err
+	}
+	a.pending.Remove(filePath)
+	return job, nil
+}
+
+// SkipPendingClip dismisses a pending clip without sending it
+func (a *App) SkipPendingClip(filePath string) error {
+	clip, ok := a.pending.Remove(filePath)
+	if !ok {
+		return errors.New("clip is not pending")
+	}
+	logger.Info("Skipped pending clip %s", clip.FileName)
+	return nil
+}
+
+// SnoozePendingClip hides a pending clip for the given number of minutes
+func (a *App) SnoozePendingClip(filePath string, minutes int) error {
+	if minutes <= 0 {
+		return errors.New("snooze time must be positive")
+	}
+	if err := a.pending.Snooze(filePath, time.Now().Add(time.Duration(minutes)*time.Minute)); err != nil {
+		return err
+	}
+	logger.Info("Snoozed %s for %d minutes", filepath.Base(filePath), minutes)
+	a.schedulePendingWake()
+	return nil
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"autoclipsend/discord"
+	"autoclipsend/logger"
+)
+
+// sentPost is one message a send left in Discord
+type sentPost struct {
+	record SendRecord
+	result DestinationResult
+	dest   Destination
+}
+
+// webhookURL addresses the post's message, which may be inside a thread
+func (p sentPost) webhookURL() string {
+	return discord.InThread(p.dest.WebhookURL, p.result.ThreadID)
+}
+
+// findPost looks up the message a job posted to a destination
+func (a *App) findPost(jobID, destinationID string) (sentPost, error) {
+	var result DestinationResult
+	record, ok := a.history.Latest(func(r SendRecord) bool {
+		if r.JobID != jobID || r.Outcome == OutcomeDeleted {
+			return false
+		}
+		for _, d := range r.Destinations {
+			if d.DestinationID == destinationID && d.Success && d.MessageID != "" {
+				result = d
+				return true
+			}
+		}
+		return false
+	})
+	if !ok {
+		return sentPost{}, errors.New("no posted message found for this send")
+	}
+	if a.history.Deleted(result.MessageID) {
+		return sentPost{}, errors.New("message was already deleted")
+	}
+
+	for _, d := range a.destinations() {
+		if d.ID == destinationID && d.WebhookURL != "" {
+			return sentPost{record: record, result: result, dest: d}, nil
+		}
+	}
+	return sentPost{}, fmt.Errorf("destination %q is no longer configured", result.Name)
+}
+
+// deletePosts removes messages from Discord and records the deletion in the history
+func (a *App) deletePosts(posts []sentPost) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	deleted := posts[0].record
+	deleted.Destinations = nil
+	deleted.Outcome = OutcomeDeleted
+	deleted.Error = ""
+	deleted.StartedAt = time.Now()
+
+	var firstErr error
+	for _, p := range posts {
+		var err error
+		for _, id := range append([]string{p.result.MessageID}, p.result.PartIDs...) {
+			// A message removed in Discord already counts as deleted
+			if delErr := a.discordClient.DeleteMessage(context.Background(), p.webhookURL(), id); delErr != nil && !errors.Is(delErr, discord.ErrNotFound) && err == nil {
+				err = delErr
+			}
+		}
+		if err != nil {
+			logger.Error("Failed to delete message %s in %s: %v", p.result.MessageID, p.dest.Name, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		logger.Info("Deleted %s from %s", p.record.FileName, p.dest.Name)
+		a.ledger.Forget(p.result.MessageID)
+		deleted.Destinations = append(deleted.Destinations, p.result)
+	}
+
+	if len(deleted.Destinations) > 0 {
+		deleted.FinishedAt = time.Now()
+		a.appendHistory(deleted)
+	}
+	return firstErr
+}
+
+// DeleteSentMessage deletes the message a send posted to one destination
+func (a *App) DeleteSentMessage(jobID, destinationID string) error {
+	post, err := a.findPost(jobID, destinationID)
+	if err != nil {
+		return err
+	}
+	return a.deletePosts([]sentPost{post})
+}
+
+// EditSentCaption replaces the caption of a posted message. With embeds the
+// caption is the embed title, so that is what changes.
+func (a *App) EditSentCaption(jobID, destinationID, caption string) error {
+	post, err := a.findPost(jobID, destinationID)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	msg, err := a.discordClient.GetMessage(ctx, post.webhookURL(), post.result.MessageID)
+	if err != nil {
+		logger.Error("Failed to fetch message %s: %v", post.result.MessageID, err)
+		return err
+	}
+
+	var edit discord.MessageEdit
+	if len(msg.Embeds) > 0 {
+		msg.Embeds[0].SetTitle(firstNonEmpty(caption, post.record.FileName))
+		edit.Embeds = msg.Embeds
+	} else {
+		edit.Content = &caption
+	}
+	if _, err := a.discordClient.EditMessage(ctx, post.webhookURL(), post.result.MessageID, edit); err != nil {
+		logger.Error("Failed to edit message %s: %v", post.result.MessageID, err)
+		return err
+	}
+	logger.Info("Edited caption of %s in %s", post.record.FileName, post.dest.Name)
+	return nil
+}
+
+// undoWindow is how long after a send UndoLastSend still applies
+func (a *App) undoWindow() time.Duration {
+	if window := a.configManager.Snapshot().UndoWindow; window > 0 {
+		return time.Duration(window) * time.Second
+	}
+	return defaultUndoWindow * time.Second
+}
+
+// lastUndoable returns the posts of the newest send still inside the undo window
+func (a *App) lastUndoable() (SendRecord, []sentPost) {
+	cutoff := time.Now().Add(-a.undoWindow())
+	record, ok := a.history.Latest(func(r SendRecord) bool {
+		return r.Outcome == OutcomeSent || r.Outcome == OutcomePartial
+	})
+	if !ok || record.FinishedAt.Before(cutoff) {
+		return SendRecord{}, nil
+	}
+
+	var posts []sentPost
+	for _, d := range record.Destinations {
+		if !d.Success || d.MessageID == "" {
+			continue
+		}
+		if post, err := a.findPost(record.JobID, d.DestinationID); err == nil {
+			posts = append(posts, post)
+		}
+	}
+	return record, posts
+}
+
+// GetUndoableSend returns the send UndoLastSend would take back, or nil
+func (a *App) GetUndoableSend() *SendRecord {
+	record, posts := a.lastUndoable()
+	if len(posts) == 0 {
+		return nil
+	}
+	return &record
+}
+
+// UndoLastSend deletes every message of the most recent send, if it finished
+// within the undo window
+func (a *App) UndoLastSend() error {
+	record, posts := a.lastUndoable()
+	if len(posts) == 0 {
+		return errors.New("nothing to undo")
+	}
+	logger.Info("Undoing send of %s", record.FileName)
+	return a.deletePosts(posts)
+}
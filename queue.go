@@ -0,0 +1,503 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"autoclipsend/logger"
+)
+
+// JobState is the lifecycle state of a queued send
+type JobState string
+
+const (
+	JobPending     JobState = "pending"
+	JobCompressing JobState = "compressing"
+	JobUploading   JobState = "uploading"
+	JobDone        JobState = "done"
+	JobFailed      JobState = "failed"
+	JobCancelled   JobState = "cancelled"
+)
+
+// maxFinishedJobs is how many done/failed/cancelled jobs are kept on disk
+const maxFinishedJobs = 100
+
+// errUploadInterrupted fails a job whose upload was cut off by an exit. Discord
+// may have posted it already, so it is left for the user to check and retry.
+var errUploadInterrupted = errors.New("upload was interrupted by the app closing and may have been posted, check the channel before retrying")
+
+// SendJob is a single clip waiting to be (or already) sent to Discord
+type SendJob struct {
+	ID         string    `json:"id"`
+	FilePath   string    `json:"filePath"`
+	CustomName string    `json:"customName"`
+	AudioOnly  bool      `json:"audioOnly"`
+	Priority   int       `json:"priority"` // Higher priority jobs run first
+	State      JobState  `json:"state"`
+	Attempts   int       `json:"attempts"`
+	Error      string    `json:"error,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+
+	Results []DestinationResult `json:"results,omitempty"` // Outcome per destination, kept across retries
+}
+
+// clone returns a copy that shares no slices with the original
+func (j *SendJob) clone() SendJob {
+	c := *j
+	c.Results = append([]DestinationResult(nil), j.Results...)
+	return c
+}
+
+// sentTo reports whether an earlier attempt already delivered the job to a destination
+func (j *SendJob) sentTo(destinationID string) bool {
+	for _, r := range j.Results {
+		if r.DestinationID == destinationID && r.Success {
+			return true
+		}
+	}
+	return false
+}
+
+// isFinished reports whether the job will not run again unless retried
+func (j *SendJob) isFinished() bool {
+	return j.State == JobDone || j.State == JobFailed || j.State == JobCancelled
+}
+
+// SendQueue is a persistent job queue processed by a pool of workers
+type SendQueue struct {
+	path     string
+	handler  func(ctx context.Context, job *SendJob) error
+	onChange func()
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	jobs    []*SendJob
+	waiters map[string][]chan error
+	cancels map[string]context.CancelFunc // Running jobs, keyed by ID
+	stopped bool
+	running sync.WaitGroup // Handlers that haven't returned yet
+}
+
+// NewSendQueue creates a queue persisted at path. handler runs each job and
+// must give up promptly once its context is cancelled.
+func NewSendQueue(path string, handler func(ctx context.Context, job *SendJob) error) *SendQueue {
+	q := &SendQueue{
+		path:    path,
+		handler: handler,
+		waiters: make(map[string][]chan error),
+		cancels: make(map[string]context.CancelFunc),
+	}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// Load restores jobs saved by a previous run. Jobs interrupted before their
+// upload are put back to pending so they resume, interrupted uploads fail
+// because they may have been posted. A file that can't be parsed is moved
+// aside so the next save doesn't overwrite it.
+func (q *SendQueue) Load() error {
+	data, err := os.ReadFile(q.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var jobs []*SendJob
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		aside := fmt.Sprintf("%s.corrupt-%s", q.path, time.Now().Format("20060102-150405"))
+		if renameErr := os.Rename(q.path, aside); renameErr != nil {
+			return fmt.Errorf("failed to parse send queue: %v, and to move it aside: %v", err, renameErr)
+		}
+		logger.Error("Send queue could not be parsed, moved it to %s and starting empty: %v", aside, err)
+		return nil
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	resumed, failed := 0, 0
+	for _, job := range jobs {
+		switch job.State {
+		case JobCompressing:
+			job.State = JobPending
+			job.UpdatedAt = time.Now()
+			resumed++
+		case JobUploading:
+			job.State = JobFailed
+			job.Error = errUploadInterrupted.Error()
+			job.UpdatedAt = time.Now()
+			failed++
+		}
+	}
+	q.jobs = jobs
+	if resumed > 0 {
+		logger.Info("Resuming %d interrupted send jobs", resumed)
+	}
+	if failed > 0 {
+		logger.Warn("%d send jobs were interrupted while uploading and need to be retried by hand", failed)
+		q.saveLocked()
+	}
+	return nil
+}
+
+// Start launches the worker pool
+func (q *SendQueue) Start(workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+	logger.Info("Starting send queue with %d workers", workers)
+	for i := 0; i < workers; i++ {
+		go q.worker()
+	}
+}
+
+// Stop tells the workers not to pick up any more jobs, cancels the running
+// ones and waits for them to give up. Jobs stopped before their upload resume
+// on the next start, stopped uploads fail so they aren't posted twice.
+func (q *SendQueue) Stop() {
+	q.mu.Lock()
+	q.stopped = true
+	for _, cancel := range q.cancels {
+		cancel()
+	}
+	q.cond.Broadcast()
+	q.mu.Unlock()
+
+	q.running.Wait()
+}
+
+// Enqueue adds a new job and returns a copy of it
+func (q *SendQueue) Enqueue(filePath, customName string, audioOnly bool) SendJob {
+	now := time.Now()
+	job := &SendJob{
+		ID:         newJobID(),
+		FilePath:   filePath,
+		CustomName: customName,
+		AudioOnly:  audioOnly,
+		State:      JobPending,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	q.mu.Lock()
+	q.jobs = append(q.jobs, job)
+	q.saveLocked()
+	q.cond.Signal()
+	q.mu.Unlock()
+
+	logger.Info("Queued send job %s for %s", job.ID, filePath)
+	q.changed()
+	return job.clone()
+}
+
+// Wait blocks until the job finishes and returns its error, if any
+func (q *SendQueue) Wait(id string) error {
+	q.mu.Lock()
+	job := q.findLocked(id)
+	if job == nil {
+		q.mu.Unlock()
+		return errors.New("job not found")
+	}
+	if job.isFinished() {
+		err := jobError(job)
+		q.mu.Unlock()
+		return err
+	}
+	ch := make(chan error, 1)
+	q.waiters[id] = append(q.waiters[id], ch)
+	q.mu.Unlock()
+
+	return <-ch
+}
+
+// List returns a snapshot of all jobs, oldest first
+func (q *SendQueue) List() []SendJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	jobs := make([]SendJob, 0, len(q.jobs))
+	for _, job := range q.jobs {
+		jobs = append(jobs, job.clone())
+	}
+	return jobs
+}
+
+// SetState moves a running job to another in-progress state
+func (q *SendQueue) SetState(id string, state JobState) {
+	q.mu.Lock()
+	if job := q.findLocked(id); job != nil {
+		job.State = state
+		job.UpdatedAt = time.Now()
+		q.saveLocked()
+	}
+	q.mu.Unlock()
+	q.changed()
+}
+
+// RecordResult stores the outcome of sending a job to one destination
+func (q *SendQueue) RecordResult(id string, result DestinationResult) {
+	q.mu.Lock()
+	if job := q.findLocked(id); job != nil {
+		replaced := false
+		for i := range job.Results {
+			if job.Results[i].DestinationID == result.DestinationID {
+				job.Results[i] = result
+				replaced = true
+			}
+		}
+		if !replaced {
+			job.Results = append(job.Results, result)
+		}
+		job.UpdatedAt = time.Now()
+		q.saveLocked()
+	}
+	q.mu.Unlock()
+	q.changed()
+}
+
+// Retry puts a failed or cancelled job back into the queue
+func (q *SendQueue) Retry(id string) error {
+	q.mu.Lock()
+	job := q.findLocked(id)
+	if job == nil {
+		q.mu.Unlock()
+		return errors.New("job not found")
+	}
+	if job.State != JobFailed && job.State != JobCancelled {
+		q.mu.Unlock()
+		return fmt.Errorf("job is %s and cannot be retried", job.State)
+	}
+	job.State = JobPending
+	job.Error = ""
+	job.UpdatedAt = time.Now()
+	q.saveLocked()
+	q.cond.Signal()
+	q.mu.Unlock()
+
+	logger.Info("Retrying send job %s", id)
+	q.changed()
+	return nil
+}
+
+// SetPriority changes the priority of a pending job
+func (q *SendQueue) SetPriority(id string, priority int) error {
+	q.mu.Lock()
+	job := q.findLocked(id)
+	if job == nil {
+		q.mu.Unlock()
+		return errors.New("job not found")
+	}
+	if job.State != JobPending {
+		q.mu.Unlock()
+		return fmt.Errorf("job is %s and can no longer be reprioritized", job.State)
+	}
+	job.Priority = priority
+	job.UpdatedAt = time.Now()
+	q.saveLocked()
+	q.mu.Unlock()
+
+	q.changed()
+	return nil
+}
+
+// Cancel removes a pending job from the queue or aborts a running one
+func (q *SendQueue) Cancel(id string) error {
+	q.mu.Lock()
+	job := q.findLocked(id)
+	if job == nil {
+		q.mu.Unlock()
+		return errors.New("job not found")
+	}
+	if cancel, running := q.cancels[id]; running {
+		// The worker marks the job cancelled once the handler returns
+		cancel()
+		q.mu.Unlock()
+		logger.Info("Cancelling running send job %s", id)
+		return nil
+	}
+	if job.State != JobPending {
+		q.mu.Unlock()
+		return fmt.Errorf("job is %s and cannot be cancelled", job.State)
+	}
+	q.finishLocked(job, JobCancelled, context.Canceled)
+	q.mu.Unlock()
+
+	logger.Info("Cancelled send job %s", id)
+	q.changed()
+	return nil
+}
+
+// worker runs jobs until the queue is stopped
+func (q *SendQueue) worker() {
+	for {
+		q.mu.Lock()
+		var job *SendJob
+		for !q.stopped {
+			if job = q.nextLocked(); job != nil {
+				break
+			}
+			q.cond.Wait()
+		}
+		if q.stopped {
+			q.mu.Unlock()
+			return
+		}
+		job.State = JobCompressing
+		job.Attempts++
+		job.UpdatedAt = time.Now()
+		q.saveLocked()
+		snapshot := job.clone()
+		ctx, cancel := context.WithCancel(context.Background())
+		q.cancels[job.ID] = cancel
+		q.running.Add(1)
+		q.mu.Unlock()
+		q.changed()
+
+		err := q.handler(ctx, &snapshot)
+
+		q.mu.Lock()
+		delete(q.cancels, job.ID)
+		switch {
+		case ctx.Err() != nil && q.stopped && job.State == JobUploading:
+			q.finishLocked(job, JobFailed, errUploadInterrupted)
+		case ctx.Err() != nil && q.stopped:
+			// Stopped by the app closing, not the user
+			job.State = JobPending
+			job.UpdatedAt = time.Now()
+			q.saveLocked()
+		case ctx.Err() != nil:
+			q.finishLocked(job, JobCancelled, context.Canceled)
+		case err != nil:
+			q.finishLocked(job, JobFailed, err)
+		default:
+			q.finishLocked(job, JobDone, nil)
+		}
+		q.mu.Unlock()
+		cancel()
+		q.running.Done()
+		q.changed()
+	}
+}
+
+// nextLocked picks the highest priority pending job, oldest first
+func (q *SendQueue) nextLocked() *SendJob {
+	var next *SendJob
+	for _, job := range q.jobs {
+		if job.State != JobPending {
+			continue
+		}
+		if next == nil || job.Priority > next.Priority ||
+			(job.Priority == next.Priority && job.CreatedAt.Before(next.CreatedAt)) {
+			next = job
+		}
+	}
+	return next
+}
+
+// finishLocked records the outcome of a job and wakes anyone waiting on it
+func (q *SendQueue) finishLocked(job *SendJob, state JobState, err error) {
+	job.State = state
+	job.UpdatedAt = time.Now()
+	job.Error = ""
+	if err != nil {
+		job.Error = err.Error()
+	}
+
+	for _, ch := range q.waiters[job.ID] {
+		ch <- err
+	}
+	delete(q.waiters, job.ID)
+
+	q.pruneLocked()
+	q.saveLocked()
+}
+
+// pruneLocked drops the oldest finished jobs beyond maxFinishedJobs
+func (q *SendQueue) pruneLocked() {
+	var finished []*SendJob
+	for _, job := range q.jobs {
+		if job.isFinished() {
+			finished = append(finished, job)
+		}
+	}
+	if len(finished) <= maxFinishedJobs {
+		return
+	}
+
+	sort.Slice(finished, func(i, j int) bool { return finished[i].UpdatedAt.Before(finished[j].UpdatedAt) })
+	drop := make(map[*SendJob]bool)
+	for _, job := range finished[:len(finished)-maxFinishedJobs] {
+		drop[job] = true
+	}
+
+	kept := q.jobs[:0]
+	for _, job := range q.jobs {
+		if !drop[job] {
+			kept = append(kept, job)
+		}
+	}
+	q.jobs = kept
+}
+
+func (q *SendQueue) findLocked(id string) *SendJob {
+	for _, job := range q.jobs {
+		if job.ID == id {
+			return job
+		}
+	}
+	return nil
+}
+
+// saveLocked writes the queue to disk, replacing the file atomically
+func (q *SendQueue) saveLocked() {
+	data, err := json.MarshalIndent(q.jobs, "", "  ")
+	if err != nil {
+		logger.Error("Failed to encode send queue: %v", err)
+		return
+	}
+
+	tmpPath := q.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		logger.Error("Failed to write send queue: %v", err)
+		return
+	}
+	if err := os.Rename(tmpPath, q.path); err != nil {
+		logger.Error("Failed to save send queue: %v", err)
+	}
+}
+
+func (q *SendQueue) changed() {
+	if q.onChange != nil {
+		q.onChange()
+	}
+}
+
+// jobError rebuilds the error of a finished job
+func jobError(job *SendJob) error {
+	if job.State == JobDone {
+		return nil
+	}
+	if job.Error == "" {
+		return fmt.Errorf("job %s", job.State)
+	}
+	return errors.New(job.Error)
+}
+
+// newJobID returns a short random identifier
+func newJobID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
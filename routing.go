@@ -0,0 +1,277 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"autoclipsend/logger"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// legacyDestinationID identifies the destination built from the old single WebhookURL setting
+const legacyDestinationID = "default"
+
+// Destination is a named Discord webhook that clips can be sent to
+type Destination struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	WebhookURL string `json:"webhook_url"`
+	Enabled    bool   `json:"enabled"`
+	Default    bool   `json:"default"`    // Receives clips that no routing rule matches
+	Username   string `json:"username"`   // Overrides the global webhook username if set
+	AvatarURL  string `json:"avatar_url"` // Overrides the global webhook avatar if set
+
+	CaptionTemplate  string `json:"caption_template"`  // Overrides the global caption template if set
+	FilenameTemplate string `json:"filename_template"` // Overrides the global filename template if set
+
+	// Threads and forum channels
+	ThreadID           string   `json:"thread_id"`            // Post into this thread or forum post instead of the channel
+	ForumPost          bool     `json:"forum_post"`           // Start a new forum post per clip, the webhook must belong to a forum channel
+	ThreadNameTemplate string   `json:"thread_name_template"` // Title of new forum posts, "{title}" if empty
+	ForumTags          []string `json:"forum_tags"`           // Tag IDs applied to new forum posts
+}
+
+// RouteRule sends clips that match all of its non-empty conditions to its destinations
+type RouteRule struct {
+	Name         string   `json:"name"`
+	Source       string   `json:"source"`       // medaltv, nvidia, custom or empty for any source
+	Subfolder    string   `json:"subfolder"`    // Folder relative to the watched path, matches nested folders too
+	GameTitle    string   `json:"game_title"`   // Medal TV game title, case-insensitive
+	Destinations []string `json:"destinations"` // Destination IDs
+}
+
+// DestinationResult is the outcome of sending one clip to one destination
+type DestinationResult struct {
+	DestinationID string    `json:"destinationId"`
+	Name          string    `json:"name"`
+	Success       bool      `json:"success"`
+	Error         string    `json:"error,omitempty"`
+	SentAt        time.Time `json:"sentAt"`
+	MessageID     string    `json:"messageId,omitempty"` // Posted message, for edits and deletes
+	ChannelID     string    `json:"channelId,omitempty"`
+	ThreadID      string    `json:"threadId,omitempty"` // Thread or forum post the message went to, reused for follow-ups
+	PartIDs       []string  `json:"partIds,omitempty"`  // Further messages of a clip sent in parts
+}
+
+// matches reports whether the clip satisfies every condition of the rule
+func (r RouteRule) matches(clip ClipInfo) bool {
+	if r.Source != "" && !strings.EqualFold(r.Source, clip.Source) {
+		return false
+	}
+	if r.Subfolder != "" && !subfolderMatches(r.Subfolder, clip.Subfolder) {
+		return false
+	}
+	if r.GameTitle != "" && !strings.EqualFold(strings.TrimSpace(r.GameTitle), strings.TrimSpace(clip.GameTitle)) {
+		return false
+	}
+	return true
+}
+
+// subfolderMatches checks if actual is the wanted folder or one of its subfolders
+func subfolderMatches(want, actual string) bool {
+	want = strings.ToLower(filepath.Clean(strings.Trim(want, `/\`)))
+	actual = strings.ToLower(filepath.Clean(actual))
+	return actual == want || strings.HasPrefix(actual, want+string(filepath.Separator))
+}
+
+// destinations returns every configured destination. The legacy WebhookURL
+// setting is exposed as the "default" destination so older configs keep working.
+func (a *App) destinations() []Destination {
+	config := a.configManager.Snapshot()
+	return configDestinations(&config)
+}
+
+// configDestinations returns the destinations of config, see destinations
+func configDestinations(config *Config) []Destination {
+	dests := slices.Clone(config.Destinations)
+	if config.WebhookURL == "" {
+		return dests
+	}
+
+	hasDefault := false
+	for _, d := range dests {
+		if d.ID == legacyDestinationID {
+			return dests
+		}
+		if d.Default && d.Enabled {
+			hasDefault = true
+		}
+	}
+
+	legacy := Destination{
+		ID:         legacyDestinationID,
+		Name:       "Default",
+		WebhookURL: config.WebhookURL,
+		Enabled:    true,
+		Default:    !hasDefault,
+	}
+	return append([]Destination{legacy}, dests...)
+}
+
+// resolveDestinations picks the destinations a clip should be sent to.
+// Every matching rule contributes its destinations; with no match the
+// clip goes to the default destinations.
+func (a *App) resolveDestinations(clip ClipInfo) []Destination {
+	all := a.destinations()
+	byID := make(map[string]Destination, len(all))
+	for _, d := range all {
+		if d.Enabled && d.WebhookURL != "" {
+			byID[d.ID] = d
+		}
+	}
+
+	var result []Destination
+	seen := make(map[string]bool)
+	for _, rule := range a.configManager.Snapshot().Routes {
+		if !rule.matches(clip) {
+			continue
+		}
+		logger.Debug("Routing rule %q matched %s", rule.Name, clip.FileName)
+		for _, id := range rule.Destinations {
+			if d, ok := byID[id]; ok && !seen[id] {
+				seen[id] = true
+				result = append(result, d)
+			}
+		}
+	}
+	if len(result) > 0 {
+		return result
+	}
+
+	// The watch entry that found the clip may name its own destination
+	if entry, ok := a.watchEntry(clip.WatchID); ok && entry.Destination != "" {
+		if d, usable := byID[entry.Destination]; usable {
+			return []Destination{d}
+		}
+	}
+
+	for _, d := range all {
+		if _, usable := byID[d.ID]; usable && d.Default {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// hasForumPost reports whether any destination starts or reuses forum posts
+func hasForumPost(destinations []Destination) bool {
+	for _, dest := range destinations {
+		if dest.ForumPost && dest.ThreadID == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// threadFor picks the thread a clip goes to at a destination. Forum
+// destinations reuse the post made for an earlier send of the same clip, so
+// an audio-only version lands under the video.
+func (a *App) threadFor(dest Destination, hash string) string {
+	if dest.ThreadID != "" {
+		return dest.ThreadID
+	}
+	if !dest.ForumPost || hash == "" {
+		return ""
+	}
+	var latest SentEntry
+	for _, e := range a.ledger.Find(hash) {
+		if e.DestinationID == dest.ID && e.ThreadID != "" && e.SentAt.After(latest.SentAt) {
+			latest = e
+		}
+	}
+	return latest.ThreadID
+}
+
+// GetDestinations returns all configured destinations, including the legacy webhook
+func (a *App) GetDestinations() []Destination {
+	return a.destinations()
+}
+
+// GetClipDestinations previews where a file would be sent by the routing rules
+func (a *App) GetClipDestinations(filePath string) []Destination {
+	return a.resolveDestinations(a.describeClip(filePath))
+}
+
+// GetRoutes returns the routing rules
+func (a *App) GetRoutes() []RouteRule {
+	return a.configManager.Snapshot().Routes
+}
+
+// validateWebhookURL checks that url is a Discord webhook
+func validateWebhookURL(webhookURL string) error {
+	u, err := url.Parse(webhookURL)
+	if err != nil || u.Scheme != "https" || !strings.Contains(u.Path, "/api/webhooks/") {
+		return errors.New("not a Discord webhook URL")
+	}
+	return nil
+}
+
+// SetDestinations replaces the configured destinations. The legacy webhook
+// setting isn't part of the list, it keeps its own field on the settings page.
+func (a *App) SetDestinations(dests []Destination) error {
+	seen := make(map[string]bool, len(dests))
+	for i := range dests {
+		dest := &dests[i]
+		if dest.ID == "" {
+			dest.ID = fmt.Sprintf("dest-%d-%d", time.Now().UnixNano(), i)
+		}
+		if seen[dest.ID] {
+			return fmt.Errorf("destination %d: duplicate ID %q", i+1, dest.ID)
+		}
+		seen[dest.ID] = true
+		if strings.TrimSpace(dest.Name) == "" {
+			return fmt.Errorf("destination %d: a name is required", i+1)
+		}
+		if err := validateWebhookURL(dest.WebhookURL); err != nil {
+			return fmt.Errorf("destination %q: %w", dest.Name, err)
+		}
+	}
+	return a.updateRouting(func(c *Config) error {
+		c.Destinations = dests
+		return nil
+	})
+}
+
+// SetRoutes replaces the routing rules. Every rule must name at least one
+// destination that exists.
+func (a *App) SetRoutes(routes []RouteRule) error {
+	return a.updateRouting(func(c *Config) error {
+		known := make(map[string]bool)
+		for _, d := range configDestinations(c) {
+			known[d.ID] = true
+		}
+		for i, rule := range routes {
+			switch rule.Source {
+			case "", SourceMedalTV, SourceNVIDIA, SourceCustom:
+			default:
+				return fmt.Errorf("rule %d: unknown source %q", i+1, rule.Source)
+			}
+			if len(rule.Destinations) == 0 {
+				return fmt.Errorf("rule %d: pick at least one destination", i+1)
+			}
+			for _, id := range rule.Destinations {
+				if !known[id] {
+					return fmt.Errorf("rule %d: unknown destination %q", i+1, id)
+				}
+			}
+		}
+		c.Routes = routes
+		return nil
+	})
+}
+
+// updateRouting saves a change to the destinations or routes and tells the frontend
+func (a *App) updateRouting(change func(*Config) error) error {
+	if err := a.configManager.Update(change); err != nil {
+		return err
+	}
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "config-updated")
+	}
+	return nil
+}
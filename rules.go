@@ -0,0 +1,257 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"autoclipsend/logger"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// AutoSendRule decides what happens to new clips that match all of its non-empty
+// conditions. The first matching rule wins; with no match the watch entry's
+// AutoSend setting applies.
+type AutoSendRule struct {
+	Name      string `json:"name"`
+	Enabled   bool   `json:"enabled"`
+	Source    string `json:"source"`     // medaltv, nvidia, custom or empty for any source
+	Path      string `json:"path"`       // Folder the clip must be in, nested folders included
+	Subfolder string `json:"subfolder"`  // Folder relative to the watched path, matches nested folders too
+	GameTitle string `json:"game_title"` // Medal TV game title, case-insensitive
+	Action    string `json:"action"`     // AutoSendAsk, AutoSendVideo, AutoSendAudio or AutoSendIgnore
+	Countdown int    `json:"countdown"`  // Seconds to wait before auto-sending so the user can cancel, 0 sends right away
+}
+
+// autoSendDecision is the action chosen for one clip and why
+type autoSendDecision struct {
+	Action    string
+	Countdown time.Duration
+	Reason    string
+}
+
+// AutoSendCountdown is a clip that will be sent when its countdown runs out
+type AutoSendCountdown struct {
+	FilePath string    `json:"filePath"`
+	FileName string    `json:"fileName"`
+	Action   string    `json:"action"`
+	Reason   string    `json:"reason"`
+	SendAt   time.Time `json:"sendAt"`
+
+	timer *time.Timer
+}
+
+// matches reports whether the clip satisfies every condition of the rule
+func (r AutoSendRule) matches(clip ClipInfo) bool {
+	if r.Source != "" && !strings.EqualFold(r.Source, clip.Source) {
+		return false
+	}
+	if r.Path != "" && !pathWithin(r.Path, clip.FilePath) {
+		return false
+	}
+	if r.Subfolder != "" && !subfolderMatches(r.Subfolder, clip.Subfolder) {
+		return false
+	}
+	if r.GameTitle != "" && !strings.EqualFold(strings.TrimSpace(r.GameTitle), strings.TrimSpace(clip.GameTitle)) {
+		return false
+	}
+	return true
+}
+
+// pathWithin checks if file is inside dir or one of its subfolders
+func pathWithin(dir, file string) bool {
+	rel, err := filepath.Rel(strings.ToLower(filepath.Clean(dir)), strings.ToLower(filepath.Clean(file)))
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// validAutoSendAction reports whether action is one the rules engine knows
+func validAutoSendAction(action string) bool {
+	switch action {
+	case AutoSendAsk, AutoSendVideo, AutoSendAudio, AutoSendIgnore:
+		return true
+	}
+	return false
+}
+
+// decideAutoSend evaluates the rules, then the watch entry, for a new clip
+func (a *App) decideAutoSend(clip ClipInfo) autoSendDecision {
+	for _, rule := range a.configManager.Snapshot().AutoSendRules {
+		if !rule.Enabled || !validAutoSendAction(rule.Action) || !rule.matches(clip) {
+			continue
+		}
+		return autoSendDecision{
+			Action:    rule.Action,
+			Countdown: time.Duration(rule.Countdown) * time.Second,
+			Reason:    fmt.Sprintf("rule %q", rule.Name),
+		}
+	}
+
+	if entry, ok := a.watchEntry(clip.WatchID); ok && validAutoSendAction(entry.AutoSend) {
+		return autoSendDecision{Action: entry.AutoSend, Reason: fmt.Sprintf("folder %q", entry.Name)}
+	}
+	return autoSendDecision{Action: AutoSendAsk, Reason: "default"}
+}
+
+// applyAutoSend carries out the action chosen for a new clip and logs it.
+// It returns false when the user should be asked instead.
+func (a *App) applyAutoSend(filePath string) bool {
+	clip := a.describeClip(filePath)
+	decision := a.decideAutoSend(clip)
+	logger.Info("Clip %s: %s (%s)", clip.FileName, decision.Action, decision.Reason)
+
+	switch decision.Action {
+	case AutoSendIgnore:
+		return true
+	case AutoSendVideo, AutoSendAudio:
+		if decision.Countdown > 0 {
+			a.startCountdown(clip, decision)
+			return true
+		}
+		return a.autoSend(filePath, decision.Action == AutoSendAudio)
+	}
+	return false
+}
+
+// autoSend queues a clip without asking, returning false if it couldn't be queued
+func (a *App) autoSend(filePath string, audioOnly bool) bool {
+	if _, err := a.QueueSend(filePath, "", audioOnly); err != nil {
+		logger.Error("Auto-send of %s failed, asking instead: %v", filepath.Base(filePath), err)
+		return false
+	}
+	return true
+}
+
+// startCountdown sends the clip after the decision's countdown unless it's cancelled first
+func (a *App) startCountdown(clip ClipInfo, decision autoSendDecision) {
+	countdown := &AutoSendCountdown{
+		FilePath: clip.FilePath,
+		FileName: clip.FileName,
+		Action:   decision.Action,
+		Reason:   decision.Reason,
+		SendAt:   time.Now().Add(decision.Countdown),
+	}
+
+	a.countdownMutex.Lock()
+	if a.countdowns[clip.FilePath] != nil {
+		a.countdownMutex.Unlock()
+		return
+	}
+	a.countdowns[clip.FilePath] = countdown
+	countdown.timer = time.AfterFunc(decision.Countdown, func() {
+		if !a.takeCountdown(clip.FilePath, countdown) {
+			return // Cancelled or sent early
+		}
+		logger.Info("Countdown for %s finished, sending", clip.FileName)
+		if !a.autoSend(clip.FilePath, decision.Action == AutoSendAudio) {
+			a.ShowNotification(clip.FileName, clip.FilePath)
+		}
+	})
+	a.countdownMutex.Unlock()
+
+	a.emitCountdowns()
+	what := "the clip"
+	if decision.Action == AutoSendAudio {
+		what = "the audio"
+	}
+	message := fmt.Sprintf("Sending %s of %s in %d seconds. Open AutoClipSend to cancel.",
+		what, clip.FileName, int(decision.Countdown.Seconds()))
+	go func() {
+		if err := a.notificationHandler.SendSystemNotification("AutoClipSend", message); err != nil {
+			logger.Warn("Failed to show countdown notification: %v", err)
+		}
+	}()
+}
+
+// takeCountdown removes a pending countdown if it is still the given one
+func (a *App) takeCountdown(filePath string, countdown *AutoSendCountdown) bool {
+	a.countdownMutex.Lock()
+	if a.countdowns[filePath] != countdown {
+		a.countdownMutex.Unlock()
+		return false
+	}
+	delete(a.countdowns, filePath)
+	a.countdownMutex.Unlock()
+
+	a.emitCountdowns()
+	return true
+}
+
+// emitCountdowns tells the frontend which clips are about to be sent
+func (a *App) emitCountdowns() {
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "autoSendCountdownsUpdated", a.GetAutoSendCountdowns())
+	}
+}
+
+// GetAutoSendCountdowns returns the clips waiting for their countdown to finish
+func (a *App) GetAutoSendCountdowns() []AutoSendCountdown {
+	a.countdownMutex.Lock()
+	defer a.countdownMutex.Unlock()
+	list := make([]AutoSendCountdown, 0, len(a.countdowns))
+	for _, c := range a.countdowns {
+		list = append(list, *c)
+	}
+	return list
+}
+
+// CancelAutoSend stops a countdown so the clip isn't sent
+func (a *App) CancelAutoSend(filePath string) {
+	a.countdownMutex.Lock()
+	countdown := a.countdowns[filePath]
+	a.countdownMutex.Unlock()
+	if countdown == nil || !a.takeCountdown(filePath, countdown) {
+		return
+	}
+	countdown.timer.Stop()
+	logger.Info("Auto-send of %s cancelled by user", countdown.FileName)
+}
+
+// SendAutoSendNow skips the rest of a countdown
+func (a *App) SendAutoSendNow(filePath string) error {
+	a.countdownMutex.Lock()
+	countdown := a.countdowns[filePath]
+	a.countdownMutex.Unlock()
+	if countdown == nil || !a.takeCountdown(filePath, countdown) {
+		return fmt.Errorf("no countdown for %s", filepath.Base(filePath))
+	}
+	countdown.timer.Stop()
+	_, err := a.QueueSend(filePath, "", countdown.Action == AutoSendAudio)
+	return err
+}
+
+// GetAutoSendRules returns the rules in evaluation order
+func (a *App) GetAutoSendRules() []AutoSendRule {
+	return a.configManager.Snapshot().AutoSendRules
+}
+
+// validateAutoSendRules checks the action and countdown of every rule
+func validateAutoSendRules(rules []AutoSendRule) error {
+	for i, rule := range rules {
+		if !validAutoSendAction(rule.Action) {
+			return fmt.Errorf("rule %d: unknown action %q", i+1, rule.Action)
+		}
+		if rule.Countdown < 0 {
+			return fmt.Errorf("rule %d: countdown can't be negative", i+1)
+		}
+	}
+	return nil
+}
+
+// SetAutoSendRules replaces the rules, keeping their order
+func (a *App) SetAutoSendRules(rules []AutoSendRule) error {
+	if err := validateAutoSendRules(rules); err != nil {
+		return err
+	}
+	err := a.configManager.Update(func(c *Config) error {
+		c.AutoSendRules = rules
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "config-updated")
+	}
+	return nil
+}
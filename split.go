@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"autoclipsend/discord"
+	"autoclipsend/encode"
+	"autoclipsend/logger"
+)
+
+// What to do with a video that is over the size limit
+const (
+	OversizeCompress = "compress" // Re-encode it to fit (default)
+	OversizeSplit    = "split"    // Cut it into parts that each fit, keeping the original quality
+)
+
+// maxSplitParts bounds how many parts a clip is cut into. Longer clips have
+// their oversized parts compressed, which still looks better than squeezing
+// the whole clip.
+const maxSplitParts = 10
+
+// splitVideo cuts a clip at keyframes into the fewest parts that each fit
+// within maxSizeBytes, without re-encoding
+func (a *App) splitVideo(ctx context.Context, jobID, inputPath, workDir string, maxSizeBytes int64) ([]string, error) {
+	info, err := a.transcoder.Probe(ctx, inputPath)
+	if err != nil || info.Duration <= 0 {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("could not read clip duration: %v", err)
+	}
+	size := info.Size
+	if size <= 0 {
+		fi, err := os.Stat(inputPath)
+		if err != nil {
+			return nil, err
+		}
+		size = fi.Size()
+	}
+
+	// Keyframes rarely fall exactly on the cut, so aim a little under the limit
+	budget := float64(maxSizeBytes) * (1 - encode.SizeSafetyMargin)
+	n := int(math.Ceil(float64(size) / budget))
+	if n < 2 {
+		n = 2
+	}
+	if n > maxSplitParts {
+		n = maxSplitParts
+	}
+
+	// The job's work folder is private, so plain part names can't collide
+	pattern := filepath.Join(workDir, "part%02d"+strings.ToLower(filepath.Ext(inputPath)))
+	for ; n <= maxSplitParts; n++ {
+		times := make([]float64, n-1)
+		for i := range times {
+			times[i] = info.Duration * float64(i+1) / float64(n)
+		}
+
+		logger.Info("Splitting %s into %d parts", filepath.Base(inputPath), n)
+		progress := a.newEncodeProgress(jobID, "splitting", info.Duration, 1, fmt.Sprintf("Splitting into %d parts", n))
+		parts, err := a.transcoder.Segment(ctx, inputPath, pattern, times, progress.report)
+		if err != nil {
+			removeFiles(parts)
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			logger.Error("Splitting failed: %v", err)
+			return nil, errors.New("splitting failed")
+		}
+
+		oversized := oversizedParts(parts, maxSizeBytes)
+		if len(oversized) == 0 {
+			return parts, nil
+		}
+		if n < maxSplitParts {
+			// Keyframes landed unevenly, one more part usually fixes it
+			logger.Info("%d of %d parts are over the limit, trying %d parts", len(oversized), n, n+1)
+			removeFiles(parts)
+			continue
+		}
+
+		// Out of parts: compress only the pieces that still don't fit
+		for _, i := range oversized {
+			compressed, strategy, err := a.compressVideoAggressively(ctx, jobID, parts[i], workDir, maxSizeBytes)
+			if err != nil {
+				removeFiles(parts)
+				return nil, fmt.Errorf("part %d: %w", i+1, err)
+			}
+			logger.Info("Compressed part %d/%d with %s", i+1, n, strategy)
+			os.Remove(parts[i])
+			parts[i] = compressed
+		}
+		return parts, nil
+	}
+	return nil, errors.New("could not split clip")
+}
+
+// oversizedParts returns the indexes of parts larger than maxSizeBytes
+func oversizedParts(parts []string, maxSizeBytes int64) []int {
+	var over []int
+	for i, p := range parts {
+		if fi, err := os.Stat(p); err != nil || fi.Size() > maxSizeBytes {
+			over = append(over, i)
+		}
+	}
+	return over
+}
+
+func removeFiles(paths []string) {
+	for _, p := range paths {
+		os.Remove(p)
+	}
+}
+
+// partGroups packs consecutive parts into messages. A message holds at most
+// discord.MaxAttachments files whose combined size is within maxBytes, so
+// parts share a message only when Discord accepts them together.
+func partGroups(parts []string, maxBytes int64) [][]int {
+	var groups [][]int
+	var current []int
+	var currentSize int64
+	for i, p := range parts {
+		var size int64
+		if fi, err := os.Stat(p); err == nil {
+			size = fi.Size()
+		}
+		if len(current) > 0 && (len(current) == discord.MaxAttachments || currentSize+size > maxBytes) {
+			groups = append(groups, current)
+			current, currentSize = nil, 0
+		}
+		current = append(current, i)
+		currentSize += size
+	}
+	if len(current) > 0 {
+		groups = append(groups, current)
+	}
+	return groups
+}
+
+// partCaption adds "Part 1/3", or "Parts 1-2/3" for a shared message, to a caption
+func partCaption(caption string, group []int, total int) string {
+	if total <= 1 {
+		return caption
+	}
+	label := fmt.Sprintf("Part %d/%d", group[0]+1, total)
+	if len(group) > 1 {
+		label = fmt.Sprintf("Parts %d-%d/%d", group[0]+1, group[len(group)-1]+1, total)
+	}
+	if caption == "" {
+		return label
+	}
+	return caption + " (" + label + ")"
+}
+
+// partFileName numbers the attachment name of a part, keeping the part's extension
+func partFileName(name, partPath string, index, total int) string {
+	if total <= 1 {
+		return name
+	}
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+	return fmt.Sprintf("%s_part%d%s", base, index+1, filepath.Ext(partPath))
+}
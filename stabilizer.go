@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"autoclipsend/logger"
+)
+
+// Stabilizer tuning
+const (
+	stablePolls       = 2               // Unchanged polls in a row before a file counts as finished
+	notifiedMemory    = 24 * time.Hour  // How long a notified path is ignored by later events
+	stabilizerGiveUp  = 5 * time.Minute // Stop waiting on a file that stopped growing but never became readable
+	minStabilizerPoll = 500 * time.Millisecond
+)
+
+// Stabilizer waits for newly detected files to be completely written before
+// handing them on. Each file is polled on its own goroutine until its size
+// and modification time stop changing, it can be opened exclusively and the
+// check function (ffprobe) accepts it. A path is handed on at most once, however
+// many Create/Write/Chmod events arrive for it.
+type Stabilizer struct {
+	interval func() time.Duration
+	check    func(ctx context.Context, path string) error
+	onReady  func(path string)
+
+	mu       sync.Mutex
+	pending  map[string]context.CancelFunc
+	notified map[string]time.Time // Paths already handed to onReady
+	created  map[string]time.Time // Paths created or renamed into place this session
+}
+
+// NewStabilizer creates a stabilizer that polls every interval() and calls
+// onReady once per file that passes check
+func NewStabilizer(interval func() time.Duration, check func(ctx context.Context, path string) error, onReady func(path string)) *Stabilizer {
+	return &Stabilizer{
+		interval: interval,
+		check:    check,
+		onReady:  onReady,
+		pending:  make(map[string]context.CancelFunc),
+		notified: make(map[string]time.Time),
+		created:  make(map[string]time.Time),
+	}
+}
+
+// Track starts watching path. Calls for a path that is already being watched
+// or was already handed on are ignored.
+func (s *Stabilizer) Track(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.pending[path]; ok {
+		return
+	}
+	for p, at := range s.notified {
+		if time.Since(at) > notifiedMemory {
+			delete(s.notified, p)
+		}
+	}
+	if _, ok := s.notified[path]; ok {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.pending[path] = cancel
+	go s.wait(ctx, path)
+}
+
+// NoteCreated records that path was created or renamed into place, so later
+// Write and Chmod events for it count as a new file
+func (s *Stabilizer) NoteCreated(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for p, at := range s.created {
+		if time.Since(at) > notifiedMemory {
+			delete(s.created, p)
+		}
+	}
+	s.created[path] = time.Now()
+}
+
+// Created reports whether NoteCreated was called for path recently
+func (s *Stabilizer) Created(path string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	at, ok := s.created[path]
+	return ok && time.Since(at) <= notifiedMemory
+}
+
+// Forget stops watching path and lets it be detected again, for when it was
+// renamed or deleted. A file renamed into path shows up as a new Track call.
+func (s *Stabilizer) Forget(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cancel, ok := s.pending[path]; ok {
+		cancel()
+		delete(s.pending, path)
+	}
+	delete(s.notified, path)
+	delete(s.created, path)
+}
+
+// CancelAll stops waiting on every tracked file, e.g. when monitoring is stopped
+func (s *Stabilizer) CancelAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for path, cancel := range s.pending {
+		cancel()
+		delete(s.pending, path)
+	}
+}
+
+// Pending reports how many files are still being written
+func (s *Stabilizer) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.pending)
+}
+
+func (s *Stabilizer) wait(ctx context.Context, path string) {
+	ready := false
+	defer func() {
+		s.mu.Lock()
+		// Forget or CancelAll may have replaced this entry with a newer one
+		if ctx.Err() == nil {
+			s.pending[path]()
+			delete(s.pending, path)
+			if ready {
+				s.notified[path] = time.Now()
+			}
+		} else {
+			ready = false
+		}
+		s.mu.Unlock()
+
+		if ready {
+			s.onReady(path)
+		}
+	}()
+
+	var lastSize int64 = -1
+	var lastMod time.Time
+	unchanged := 0
+	lastChange := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(max(s.interval(), minStabilizerPoll)):
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			logger.Debug("Stopped waiting on %s: %v", path, err)
+			return
+		}
+
+		if info.Size() != lastSize || !info.ModTime().Equal(lastMod) {
+			lastSize, lastMod = info.Size(), info.ModTime()
+			unchanged = 0
+			lastChange = time.Now()
+			continue
+		}
+
+		// Size has settled, make sure the recorder really let go of the file.
+		// An empty file is never ready, but is given up on like any other.
+		unchanged++
+		if unchanged >= stablePolls && info.Size() > 0 {
+			if err := openExclusive(path); err != nil {
+				logger.Debug("%s is still in use: %v", path, err)
+			} else if err := s.check(ctx, path); err != nil {
+				logger.Debug("%s is not readable yet: %v", path, err)
+			} else {
+				logger.Info("File finished writing: %s (%d bytes)", path, info.Size())
+				ready = true
+				return
+			}
+		}
+
+		if time.Since(lastChange) > stabilizerGiveUp {
+			logger.Warn("Giving up on %s, it stopped changing but never became readable", path)
+			return
+		}
+	}
+}
+
+// checkInterval is the configured stabilizer poll period
+func (a *App) checkInterval() time.Duration {
+	return time.Duration(a.configManager.Snapshot().CheckInterval) * time.Second
+}
+
+// probeReadable checks that ffprobe can parse a clip, which fails while the
+// container index hasn't been written yet
+func (a *App) probeReadable(ctx context.Context, path string) error {
+	probe, err := a.transcoder.Probe(ctx, path)
+	if err != nil {
+		return err
+	}
+	if probe.Duration <= 0 {
+		return errors.New("no duration")
+	}
+	return nil
+}
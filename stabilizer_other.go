@@ -0,0 +1,23 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"time"
+)
+
+// openExclusive only checks that the file can be opened, other platforms
+// don't have mandatory sharing modes
+func openExclusive(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// fileCreated returns the zero time, creation time isn't portably available
+func fileCreated(info os.FileInfo) time.Time {
+	return time.Time{}
+}
@@ -48,21 +48,30 @@ func (a *App) onTrayReady() {
 	// Exit
 	mExit := systray.AddMenuItem("❌ Exit", "Exit the application completely")
 
-	// Status update goroutine (refreshes status periodically)
-	go func() {
-		for {
-			if a.isMonitoring {
-				mStatusState.SetTitle("● Monitoring Active")
-				mToggleMonitoring.Check()
-				mToggleMonitoring.SetTitle("⏸️ Pause Monitoring")
-			} else {
-				mStatusState.SetTitle("○ Monitoring Paused")
-				mToggleMonitoring.Uncheck()
-				mToggleMonitoring.SetTitle("▶️ Resume Monitoring")
-			}
-			time.Sleep(1 * time.Second)
+	// Keep the menu in step with the monitor, reading the state fresh since
+	// listeners can run out of order
+	updateMonitoringItems := func(MonitorStatus) {
+		switch a.monitor.State() {
+		case MonitorRunning, MonitorStarting:
+			mStatusState.SetTitle("● Monitoring Active")
+			mToggleMonitoring.Check()
+			mToggleMonitoring.SetTitle("⏸️ Pause Monitoring")
+		case MonitorPaused:
+			mStatusState.SetTitle("○ Monitoring Paused")
+			mToggleMonitoring.Uncheck()
+			mToggleMonitoring.SetTitle("▶️ Resume Monitoring")
+		case MonitorError:
+			mStatusState.SetTitle("⚠ Monitoring Failed")
+			mToggleMonitoring.Uncheck()
+			mToggleMonitoring.SetTitle("▶️ Start Monitoring")
+		default:
+			mStatusState.SetTitle("○ Monitoring Stopped")
+			mToggleMonitoring.Uncheck()
+			mToggleMonitoring.SetTitle("▶️ Start Monitoring")
 		}
-	}()
+	}
+	a.monitor.Subscribe(updateMonitoringItems)
+	updateMonitoringItems(a.monitor.Status())
 
 	// Set click handlers for menu items
 	go func() {
@@ -73,8 +82,14 @@ func (a *App) onTrayReady() {
 				a.ShowFromTray()
 
 			case <-mToggleMonitoring.ClickedCh:
-				a.isMonitoring = !a.isMonitoring
-				runtime.EventsEmit(a.ctx, "toggle-monitoring", a.isMonitoring)
+				switch a.monitor.State() {
+				case MonitorRunning:
+					a.monitor.Pause()
+				case MonitorPaused:
+					go a.monitor.Resume()
+				case MonitorStopped, MonitorError:
+					go a.monitor.Start()
+				}
 
 			case <-mExit.ClickedCh:
 				logger.Info("Exit clicked in tray menu - shutting down app completely")
@@ -0,0 +1,142 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// maxWatchedDirs limits recursive notification watches to prevent system overload
+const maxWatchedDirs = 10000
+
+// notifyWatcher wraps fsnotify, adding watches for new subfolders when recursive
+type notifyWatcher struct {
+	fs        *fsnotify.Watcher
+	recursive bool
+	events    chan Event
+	errors    chan error
+	done      chan struct{}
+	closeOnce sync.Once
+	dirCount  int
+}
+
+func newNotifyWatcher(root string, opts Options) (*notifyWatcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := fsw.Add(root); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+
+	w := &notifyWatcher{
+		fs:        fsw,
+		recursive: opts.Recursive,
+		events:    make(chan Event, 1000),
+		errors:    make(chan error, 100),
+		done:      make(chan struct{}),
+	}
+	if w.recursive {
+		w.addSubdirectories(root)
+	}
+
+	go w.run()
+	return w, nil
+}
+
+func (w *notifyWatcher) Events() <-chan Event { return w.events }
+func (w *notifyWatcher) Errors() <-chan error { return w.errors }
+
+func (w *notifyWatcher) Close() error {
+	var err error
+	w.closeOnce.Do(func() {
+		close(w.done)
+		err = w.fs.Close()
+	})
+	return err
+}
+
+func (w *notifyWatcher) run() {
+	defer close(w.events)
+	defer close(w.errors)
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case event, ok := <-w.fs.Events:
+			if !ok {
+				return
+			}
+			if w.recursive && event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					w.add(event.Name)
+					w.addSubdirectories(event.Name)
+				}
+			}
+			select {
+			case w.events <- Event{Path: event.Name, Op: convertOp(event.Op)}:
+			case <-w.done:
+				return
+			}
+		case err, ok := <-w.fs.Errors:
+			if !ok {
+				return
+			}
+			select {
+			case w.errors <- err:
+			default: // Drop errors nobody is reading rather than block events
+			}
+		}
+	}
+}
+
+// addSubdirectories watches every folder below root
+func (w *notifyWatcher) addSubdirectories(root string) {
+	filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil // Continue walking despite errors
+		}
+		if d.IsDir() && path != root {
+			if w.dirCount >= maxWatchedDirs {
+				return filepath.SkipDir
+			}
+			w.add(path)
+		}
+		return nil
+	})
+}
+
+func (w *notifyWatcher) add(path string) {
+	if err := w.fs.Add(path); err != nil {
+		select {
+		case w.errors <- err:
+		default:
+		}
+		return
+	}
+	w.dirCount++
+}
+
+func convertOp(op fsnotify.Op) Op {
+	var out Op
+	if op.Has(fsnotify.Create) {
+		out |= Create
+	}
+	if op.Has(fsnotify.Write) {
+		out |= Write
+	}
+	if op.Has(fsnotify.Remove) {
+		out |= Remove
+	}
+	if op.Has(fsnotify.Rename) {
+		out |= Rename
+	}
+	if op.Has(fsnotify.Chmod) {
+		out |= Chmod
+	}
+	return out
+}
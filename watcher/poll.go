@@ -0,0 +1,151 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// fileState is what a snapshot remembers about a path
+type fileState struct {
+	size    int64
+	modTime time.Time
+	mode    os.FileMode
+	isDir   bool
+}
+
+// pollWatcher diffs periodic snapshots of a folder tree. Renames show up as
+// a Remove of the old path and a Create of the new one.
+type pollWatcher struct {
+	root      string
+	recursive bool
+	interval  time.Duration
+	events    chan Event
+	errors    chan error
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newPollWatcher(root string, opts Options) (*pollWatcher, error) {
+	// Fail early like the notify backend if root can't be read
+	if _, err := os.ReadDir(root); err != nil {
+		return nil, err
+	}
+
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	w := &pollWatcher{
+		root:      root,
+		recursive: opts.Recursive,
+		interval:  interval,
+		events:    make(chan Event, 1000),
+		errors:    make(chan error, 100),
+		done:      make(chan struct{}),
+	}
+
+	// Files that exist before watching starts are not reported
+	snapshot, err := w.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	go w.run(snapshot)
+	return w, nil
+}
+
+func (w *pollWatcher) Events() <-chan Event { return w.events }
+func (w *pollWatcher) Errors() <-chan error { return w.errors }
+
+func (w *pollWatcher) Close() error {
+	w.closeOnce.Do(func() { close(w.done) })
+	return nil
+}
+
+func (w *pollWatcher) run(previous map[string]fileState) {
+	defer close(w.events)
+	defer close(w.errors)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+		}
+
+		current, err := w.snapshot()
+		if err != nil {
+			// The mount may be briefly unavailable, keep the old snapshot and try again
+			select {
+			case w.errors <- err:
+			default:
+			}
+			continue
+		}
+
+		for _, event := range diff(previous, current) {
+			select {
+			case w.events <- event:
+			case <-w.done:
+				return
+			}
+		}
+		previous = current
+	}
+}
+
+// snapshot records every entry under root, descending into subfolders when recursive
+func (w *pollWatcher) snapshot() (map[string]fileState, error) {
+	states := make(map[string]fileState)
+	err := filepath.WalkDir(w.root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if path == w.root {
+				return err
+			}
+			return nil // Skip entries that vanished or can't be read
+		}
+		if path == w.root {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		states[path] = fileState{size: info.Size(), modTime: info.ModTime(), mode: info.Mode(), isDir: d.IsDir()}
+
+		if d.IsDir() && !w.recursive {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return states, err
+}
+
+// diff turns two snapshots into events
+func diff(previous, current map[string]fileState) []Event {
+	var events []Event
+	for path, state := range current {
+		old, existed := previous[path]
+		switch {
+		case !existed:
+			events = append(events, Event{Path: path, Op: Create})
+		case state.isDir:
+			// Folder timestamps change whenever their contents do, which is reported per file
+		case state.size != old.size || !state.modTime.Equal(old.modTime):
+			events = append(events, Event{Path: path, Op: Write})
+		case state.mode != old.mode:
+			events = append(events, Event{Path: path, Op: Chmod})
+		}
+	}
+	for path := range previous {
+		if _, ok := current[path]; !ok {
+			events = append(events, Event{Path: path, Op: Remove})
+		}
+	}
+	return events
+}
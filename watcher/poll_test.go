@@ -0,0 +1,80 @@
+package watcher
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	t0 := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	clip := fileState{size: 1000, modTime: t0, mode: 0644}
+	folder := fileState{modTime: t0, mode: os.ModeDir | 0755, isDir: true}
+
+	tests := []struct {
+		name              string
+		previous, current map[string]fileState
+		want              []Event
+	}{
+		{
+			name:     "nothing changed",
+			previous: map[string]fileState{"a.mp4": clip, "sub": folder},
+			current:  map[string]fileState{"a.mp4": clip, "sub": folder},
+			want:     nil,
+		},
+		{
+			name:     "create",
+			previous: map[string]fileState{},
+			current:  map[string]fileState{"a.mp4": clip, "sub": folder},
+			want:     []Event{{"a.mp4", Create}, {"sub", Create}},
+		},
+		{
+			name:     "remove",
+			previous: map[string]fileState{"a.mp4": clip, "sub": folder},
+			current:  map[string]fileState{},
+			want:     []Event{{"a.mp4", Remove}, {"sub", Remove}},
+		},
+		{
+			name:     "rename is a remove and a create",
+			previous: map[string]fileState{"a.mp4": clip},
+			current:  map[string]fileState{"b.mp4": clip},
+			want:     []Event{{"a.mp4", Remove}, {"b.mp4", Create}},
+		},
+		{
+			name:     "size change",
+			previous: map[string]fileState{"a.mp4": clip},
+			current:  map[string]fileState{"a.mp4": {size: 2000, modTime: t0, mode: 0644}},
+			want:     []Event{{"a.mp4", Write}},
+		},
+		{
+			name:     "modification time change",
+			previous: map[string]fileState{"a.mp4": clip},
+			current:  map[string]fileState{"a.mp4": {size: 1000, modTime: t0.Add(time.Second), mode: 0644}},
+			want:     []Event{{"a.mp4", Write}},
+		},
+		{
+			name:     "mode change",
+			previous: map[string]fileState{"a.mp4": clip},
+			current:  map[string]fileState{"a.mp4": {size: 1000, modTime: t0, mode: 0444}},
+			want:     []Event{{"a.mp4", Chmod}},
+		},
+		{
+			name:     "folder timestamp change is not reported",
+			previous: map[string]fileState{"sub": folder},
+			current:  map[string]fileState{"sub": {modTime: t0.Add(time.Minute), mode: os.ModeDir | 0755, isDir: true}},
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diff(tt.previous, tt.current)
+			sort.Slice(got, func(i, j int) bool { return got[i].Path < got[j].Path })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package watcher
+
+import "golang.org/x/sys/unix"
+
+// File system magic numbers that don't deliver inotify events for remote changes
+var remoteFileSystems = map[int64]bool{
+	0x6969:     true, // NFS
+	0xff534d42: true, // CIFS
+	0xfe534d42: true, // SMB2
+	0x517b:     true, // SMB
+	0x65735546: true, // FUSE (sshfs, rclone, ...)
+	0x564c:     true, // NCP
+	0x01021997: true, // 9P (WSL shares)
+}
+
+// IsRemote reports whether path is on a network or FUSE file system, where change notifications are unreliable
+func IsRemote(path string) bool {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return false
+	}
+	return remoteFileSystems[int64(stat.Type)]
+}
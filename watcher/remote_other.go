@@ -0,0 +1,8 @@
+//go:build !windows && !linux
+
+package watcher
+
+// IsRemote can't tell mount types apart on this platform, use the poll backend explicitly
+func IsRemote(path string) bool {
+	return false
+}
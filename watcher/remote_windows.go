@@ -0,0 +1,35 @@
+package watcher
+
+import (
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/windows"
+)
+
+// IsRemote reports whether path is on a network share or a WinFsp FUSE mount
+// (rclone, SSHFS-Win, ...), where change notifications are unreliable
+func IsRemote(path string) bool {
+	if strings.HasPrefix(path, `\\`) {
+		return true // UNC path
+	}
+
+	volume := filepath.VolumeName(path)
+	if volume == "" {
+		return false
+	}
+	root, err := windows.UTF16PtrFromString(volume + `\`)
+	if err != nil {
+		return false
+	}
+	if windows.GetDriveType(root) == windows.DRIVE_REMOTE {
+		return true
+	}
+
+	// WinFsp mounts look like local disks but name their file system "FUSE" or "FUSE-<name>"
+	var fsName [windows.MAX_PATH + 1]uint16
+	if err := windows.GetVolumeInformation(root, nil, 0, nil, nil, nil, &fsName[0], uint32(len(fsName))); err != nil {
+		return false
+	}
+	return strings.HasPrefix(strings.ToUpper(windows.UTF16ToString(fsName[:])), "FUSE")
+}
@@ -0,0 +1,106 @@
+// Package watcher reports file changes under a folder. Local disks are
+// watched through OS notifications, network and FUSE mounts, which don't
+// deliver those, are polled.
+package watcher
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Op describes what happened to a path
+type Op uint32
+
+const (
+	Create Op = 1 << iota
+	Write
+	Remove
+	Rename // The path was renamed away, the new name arrives as a Create
+	Chmod
+)
+
+// Has reports whether op includes all of other
+func (op Op) Has(other Op) bool {
+	return op&other == other
+}
+
+func (op Op) String() string {
+	var names []string
+	for _, o := range []struct {
+		op   Op
+		name string
+	}{{Create, "CREATE"}, {Write, "WRITE"}, {Remove, "REMOVE"}, {Rename, "RENAME"}, {Chmod, "CHMOD"}} {
+		if op.Has(o.op) {
+			names = append(names, o.name)
+		}
+	}
+	if len(names) == 0 {
+		return "[no events]"
+	}
+	return strings.Join(names, "|")
+}
+
+// Event is a change to a single path
+type Event struct {
+	Path string
+	Op   Op
+}
+
+func (e Event) String() string {
+	return fmt.Sprintf("%s %q", e.Op, e.Path)
+}
+
+// Watcher delivers events for one root folder until closed
+type Watcher interface {
+	Events() <-chan Event
+	Errors() <-chan error
+	Close() error
+}
+
+// Backend selects how a folder is watched
+type Backend string
+
+const (
+	BackendAuto   Backend = "auto"   // Poll network and FUSE mounts, use notifications elsewhere
+	BackendNotify Backend = "notify" // OS change notifications
+	BackendPoll   Backend = "poll"   // Periodic directory snapshots
+)
+
+// DefaultPollInterval is used when Options.Interval is not set
+const DefaultPollInterval = 5 * time.Second
+
+// Options configures a watcher
+type Options struct {
+	Recursive bool          // Also watch subfolders, including ones created later
+	Interval  time.Duration // Time between snapshots for the polling backend
+}
+
+// New watches root with the given backend. BackendAuto (or "") polls when
+// root is on a network or FUSE file system.
+func New(root string, backend Backend, opts Options) (Watcher, Backend, error) {
+	if backend == "" || backend == BackendAuto {
+		backend = BackendNotify
+		if IsRemote(root) {
+			backend = BackendPoll
+		}
+	}
+
+	// Return a plain nil on error, not a nil *notifyWatcher in a non-nil interface
+	switch backend {
+	case BackendNotify:
+		w, err := newNotifyWatcher(root, opts)
+		if err != nil {
+			return nil, backend, err
+		}
+		return w, backend, nil
+	case BackendPoll:
+		w, err := newPollWatcher(root, opts)
+		if err != nil {
+			return nil, backend, err
+		}
+		return w, backend, nil
+	default:
+		return nil, backend, fmt.Errorf("unknown watcher backend %q", backend)
+	}
+}
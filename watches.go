@@ -0,0 +1,367 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"autoclipsend/clipfilter"
+	"autoclipsend/logger"
+	"autoclipsend/watcher"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// What happens when a clip is detected, chosen by an auto-send rule or the watch entry
+const (
+	AutoSendAsk    = "ask"    // Show the notification and let the user decide (default)
+	AutoSendVideo  = "video"  // Queue the clip right away
+	AutoSendAudio  = "audio"  // Queue just the audio right away
+	AutoSendIgnore = "ignore" // Drop the clip without asking
+)
+
+// IDs of the entries that mirror the legacy MedalTV, NVIDIA and custom folder settings
+const (
+	watchIDMedalTV = SourceMedalTV
+	watchIDNVIDIA  = SourceNVIDIA
+	watchIDCustom  = SourceCustom
+)
+
+// WatchEntry is one folder watched for new clips
+type WatchEntry struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Source      string            `json:"source"` // SourceMedalTV and SourceNVIDIA entries follow those apps' configured folder, Path is unused
+	Path        string            `json:"path"`
+	Enabled     bool              `json:"enabled"`
+	Recursive   bool              `json:"recursive"`
+	Backend     string            `json:"backend"`     // "auto" (default), "notify" or "poll"
+	Filter      clipfilter.Filter `json:"filter"`      // Which files count as clips
+	Destination string            `json:"destination"` // Destination ID used when no routing rule matches, "" for the default destinations
+	AutoSend    string            `json:"auto_send"`   // AutoSendAsk, AutoSendVideo, AutoSendAudio or AutoSendIgnore, used when no auto-send rule matches
+}
+
+// activeWatch is an enabled entry with its folder resolved
+type activeWatch struct {
+	Entry WatchEntry
+	Path  string
+}
+
+// runningWatch is a watcher started for an entry, kept to detect when the entry changes
+type runningWatch struct {
+	entry   WatchEntry
+	path    string
+	backend watcher.Backend
+	opts    watcher.Options
+	w       watcher.Watcher
+}
+
+// legacyWatchEntries builds watch entries from the settings used before entries existed
+func legacyWatchEntries(config *Config) []WatchEntry {
+	entries := []WatchEntry{}
+	if config.UseMedalTVPath {
+		entries = append(entries, builtinWatchEntry(watchIDMedalTV, config))
+	}
+	if config.UseNVIDIAPath {
+		entries = append(entries, builtinWatchEntry(watchIDNVIDIA, config))
+	}
+	if config.MonitorPath != "" {
+		entries = append(entries, builtinWatchEntry(watchIDCustom, config))
+	}
+	return entries
+}
+
+// builtinWatchEntry creates the entry behind one of the legacy folder toggles
+func builtinWatchEntry(id string, config *Config) WatchEntry {
+	entry := WatchEntry{ID: id, Source: id, Recursive: config.RecursiveMonitoring, AutoSend: AutoSendAsk}
+	switch id {
+	case watchIDMedalTV:
+		entry.Name, entry.Enabled = "Medal", config.UseMedalTVPath
+	case watchIDNVIDIA:
+		entry.Name, entry.Enabled = "NVIDIA", config.UseNVIDIAPath
+	case watchIDCustom:
+		entry.Name, entry.Enabled, entry.Path = "Custom folder", config.UseCustomPath, config.MonitorPath
+	}
+	return entry
+}
+
+// applyLegacyWatchSettings carries changes made through the legacy folder
+// settings, which the settings page still edits, over to the built-in entries
+func applyLegacyWatchSettings(prev, config *Config) {
+	for _, id := range []string{watchIDMedalTV, watchIDNVIDIA, watchIDCustom} {
+		want := builtinWatchEntry(id, config)
+		i := findWatchEntry(config.Watches, id)
+		if i < 0 {
+			if want.Enabled {
+				config.Watches = append(config.Watches, want)
+			}
+			continue
+		}
+
+		entry := &config.Watches[i]
+		entry.Enabled = want.Enabled
+		if id == watchIDCustom && config.MonitorPath != prev.MonitorPath {
+			entry.Path = config.MonitorPath
+		}
+		if config.RecursiveMonitoring != prev.RecursiveMonitoring {
+			entry.Recursive = config.RecursiveMonitoring
+		}
+	}
+}
+
+// syncLegacyWatchFields mirrors the built-in entries back into the legacy
+// settings so the settings page shows the current state
+func syncLegacyWatchFields(config *Config) {
+	enabled := func(id string) bool {
+		i := findWatchEntry(config.Watches, id)
+		return i >= 0 && config.Watches[i].Enabled
+	}
+	config.UseMedalTVPath = enabled(watchIDMedalTV)
+	config.UseNVIDIAPath = enabled(watchIDNVIDIA)
+	config.UseCustomPath = enabled(watchIDCustom)
+	if i := findWatchEntry(config.Watches, watchIDCustom); i >= 0 {
+		config.MonitorPath = config.Watches[i].Path
+	}
+}
+
+func findWatchEntry(entries []WatchEntry, id string) int {
+	for i, entry := range entries {
+		if entry.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// resolveWatchPath returns the folder an entry watches
+func (a *App) resolveWatchPath(entry WatchEntry) (string, error) {
+	switch entry.Source {
+	case SourceMedalTV:
+		return a.GetMedalTVClipFolder()
+	case SourceNVIDIA:
+		return a.GetNVIDIACurrentDirectory()
+	}
+	if entry.Path == "" {
+		return "", errors.New("no folder set")
+	}
+	return entry.Path, nil
+}
+
+// activeWatches resolves every enabled entry whose folder can be found
+func (a *App) activeWatches() []activeWatch {
+	var active []activeWatch
+	for _, entry := range a.configManager.Snapshot().Watches {
+		if !entry.Enabled {
+			continue
+		}
+		path, err := a.resolveWatchPath(entry)
+		if err != nil {
+			logger.Warn("Watch %q enabled but its folder is unavailable: %v", entry.Name, err)
+			continue
+		}
+		active = append(active, activeWatch{Entry: entry, Path: path})
+	}
+	return active
+}
+
+// watchEntryFor finds the entry watching filePath, the most specific one if
+// folders are nested, along with its folder
+func (a *App) watchEntryFor(filePath string) (WatchEntry, string, bool) {
+	a.watcherMutex.Lock()
+	watches := make([]activeWatch, 0, len(a.watchers))
+	for _, running := range a.watchers {
+		watches = append(watches, activeWatch{Entry: running.entry, Path: running.path})
+	}
+	a.watcherMutex.Unlock()
+	if len(watches) == 0 {
+		// Not monitoring, e.g. a clip sent from the clips page
+		watches = a.activeWatches()
+	}
+
+	var best activeWatch
+	found := false
+	for _, watch := range watches {
+		if _, ok := relativeTo(watch.Path, filePath); ok && (!found || len(watch.Path) > len(best.Path)) {
+			best, found = watch, true
+		}
+	}
+	return best.Entry, best.Path, found
+}
+
+// watchEntry looks an entry up by ID
+func (a *App) watchEntry(id string) (WatchEntry, bool) {
+	watches := a.configManager.Snapshot().Watches
+	if i := findWatchEntry(watches, id); i >= 0 {
+		return watches[i], true
+	}
+	return WatchEntry{}, false
+}
+
+// syncWatchersLocked starts watchers for new or changed entries and stops
+// those of removed or disabled ones, leaving the rest running. The caller
+// holds watcherMutex.
+func (a *App) syncWatchersLocked() {
+	desired := make(map[string]activeWatch)
+	for _, watch := range a.activeWatches() {
+		desired[watch.Entry.ID] = watch
+	}
+
+	for id, running := range a.watchers {
+		watch, ok := desired[id]
+		if ok && samePath(running.path, watch.Path) && running.opts == a.watchOptions(watch.Entry) && running.backend == watcher.Backend(watch.Entry.Backend) {
+			running.entry = watch.Entry // Filters, destination and auto-send apply without a restart
+			continue
+		}
+		running.w.Close()
+		delete(a.watchers, id)
+		logger.Info("Stopped watching %s", running.path)
+	}
+
+	for id, watch := range desired {
+		if _, ok := a.watchers[id]; ok {
+			continue
+		}
+		if err := a.createWatcher(watch); err != nil {
+			logger.Error("Failed to create watcher for path %s: %v", watch.Path, err)
+		}
+	}
+
+	a.monitoredPaths = make([]string, 0, len(a.watchers))
+	for _, running := range a.watchers {
+		a.monitoredPaths = append(a.monitoredPaths, running.path)
+	}
+}
+
+// applyWatchChanges changes the watch entries with change, saves the config
+// and updates running watchers
+func (a *App) applyWatchChanges(change func(*Config) error) error {
+	err := a.configManager.Update(func(c *Config) error {
+		if err := change(c); err != nil {
+			return err
+		}
+		syncLegacyWatchFields(c)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if a.monitor.Active() {
+		a.watcherMutex.Lock()
+		a.syncWatchersLocked()
+		a.watcherMutex.Unlock()
+	}
+
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, "config-updated")
+	}
+	return nil
+}
+
+// validateWatchEntry fills in defaults and checks the folder of a custom entry
+func validateWatchEntry(entry *WatchEntry) error {
+	if entry.Source == "" {
+		entry.Source = SourceCustom
+	}
+	if entry.AutoSend == "" {
+		entry.AutoSend = AutoSendAsk
+	}
+	if entry.Backend == "" {
+		entry.Backend = string(watcher.BackendAuto)
+	}
+
+	switch entry.AutoSend {
+	case AutoSendAsk, AutoSendVideo, AutoSendAudio, AutoSendIgnore:
+	default:
+		return fmt.Errorf("unknown auto-send mode %q", entry.AutoSend)
+	}
+	switch watcher.Backend(entry.Backend) {
+	case watcher.BackendAuto, watcher.BackendNotify, watcher.BackendPoll:
+	default:
+		return fmt.Errorf("unknown watcher backend %q", entry.Backend)
+	}
+
+	if entry.Source == SourceCustom {
+		info, err := os.Stat(entry.Path)
+		if err != nil {
+			return fmt.Errorf("folder not found: %s", entry.Path)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("not a folder: %s", entry.Path)
+		}
+	}
+	if strings.TrimSpace(entry.Name) == "" {
+		entry.Name = entry.Path
+	}
+	return nil
+}
+
+// validateWatchEntries validates every entry of a replaced list and checks their IDs are unique
+func validateWatchEntries(entries []WatchEntry) error {
+	seen := make(map[string]bool, len(entries))
+	for i := range entries {
+		entry := &entries[i]
+		if entry.ID == "" || seen[entry.ID] {
+			return fmt.Errorf("watch entry %d: missing or duplicate ID %q", i+1, entry.ID)
+		}
+		seen[entry.ID] = true
+		if !entry.Enabled {
+			continue // A disabled entry may point at a folder that's gone
+		}
+		if err := validateWatchEntry(entry); err != nil {
+			return fmt.Errorf("watch entry %q: %w", entry.Name, err)
+		}
+	}
+	return nil
+}
+
+// GetWatchEntries returns every configured watch entry
+func (a *App) GetWatchEntries() []WatchEntry {
+	return a.configManager.Snapshot().Watches
+}
+
+// AddWatchEntry adds a folder to watch and starts watching it right away if monitoring is running
+func (a *App) AddWatchEntry(entry WatchEntry) (WatchEntry, error) {
+	if err := validateWatchEntry(&entry); err != nil {
+		return WatchEntry{}, err
+	}
+	entry.ID = fmt.Sprintf("watch-%d", time.Now().UnixNano())
+	err := a.applyWatchChanges(func(c *Config) error {
+		c.Watches = append(c.Watches, entry)
+		return nil
+	})
+	if err != nil {
+		return WatchEntry{}, err
+	}
+	logger.Info("Added watch entry %q for %s", entry.Name, entry.Path)
+	return entry, nil
+}
+
+// UpdateWatchEntry replaces the entry with the same ID, restarting only its watcher
+func (a *App) UpdateWatchEntry(entry WatchEntry) error {
+	if err := validateWatchEntry(&entry); err != nil {
+		return err
+	}
+	return a.applyWatchChanges(func(c *Config) error {
+		i := findWatchEntry(c.Watches, entry.ID)
+		if i < 0 {
+			return fmt.Errorf("watch entry %s not found", entry.ID)
+		}
+		c.Watches[i] = entry
+		return nil
+	})
+}
+
+// RemoveWatchEntry stops watching an entry's folder and deletes the entry
+func (a *App) RemoveWatchEntry(id string) error {
+	return a.applyWatchChanges(func(c *Config) error {
+		i := findWatchEntry(c.Watches, id)
+		if i < 0 {
+			return fmt.Errorf("watch entry %s not found", id)
+		}
+		logger.Info("Removed watch entry %q", c.Watches[i].Name)
+		c.Watches = append(c.Watches[:i], c.Watches[i+1:]...)
+		return nil
+	})
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"autoclipsend/logger"
+)
+
+// WorkDir hands out a scratch folder per send job under the app data path,
+// so intermediate ffmpeg output never lands in a monitored folder
+type WorkDir struct {
+	root string
+}
+
+// NewWorkDir creates a WorkDir rooted at root. Nothing is created on disk until a job needs it.
+func NewWorkDir(root string) *WorkDir {
+	return &WorkDir{root: root}
+}
+
+// Create returns an empty folder for jobID, clearing leftovers from an earlier attempt
+func (w *WorkDir) Create(jobID string) (string, error) {
+	dir := filepath.Join(w.root, jobID)
+	if err := os.RemoveAll(dir); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// Remove deletes the folder of jobID and everything in it
+func (w *WorkDir) Remove(jobID string) {
+	if err := os.RemoveAll(filepath.Join(w.root, jobID)); err != nil {
+		logger.Warn("Failed to clean up work folder for job %s: %v", jobID, err)
+	}
+}
+
+// Sweep deletes job folders left behind by a crash. It must run before any job starts.
+func (w *WorkDir) Sweep() {
+	entries, err := os.ReadDir(w.root)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warn("Failed to read work folder: %v", err)
+		}
+		return
+	}
+
+	for _, entry := range entries {
+		if err := os.RemoveAll(filepath.Join(w.root, entry.Name())); err != nil {
+			logger.Warn("Failed to remove orphaned work folder %s: %v", entry.Name(), err)
+			continue
+		}
+		logger.Info("Removed orphaned work folder %s", entry.Name())
+	}
+}
+
+// workPath names an intermediate file for input inside dir, e.g. clip_audio.mp3
+func workPath(dir, input, suffix string) string {
+	name := strings.TrimSuffix(filepath.Base(input), filepath.Ext(input))
+	return filepath.Join(dir, name+suffix)
+}